@@ -0,0 +1,210 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BBox описывает прямоугольную область, ограниченную параллелями South и North и меридианами
+// West и East (в градусах). Если West больше East, то область пересекает линию перемены дат
+// (меридиан ±180°) и включает долготы от West до 180 и от -180 до East.
+type BBox struct {
+	South float64 // минимальная широта
+	West  float64 // западная граница долготы
+	North float64 // максимальная широта
+	East  float64 // восточная граница долготы
+}
+
+// EmptyBBox описывает пустую область, которая не содержит ни одной точки.
+var EmptyBBox = BBox{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+
+// NewBBox возвращает минимальную область, содержащую все указанные точки. Если разброс точек
+// по долготе меньше при пересечении меридиана ±180°, то возвращается область, пересекающая его.
+// Если точки не указаны, то возвращается EmptyBBox.
+func NewBBox(points ...Point) BBox {
+	if len(points) == 0 {
+		return EmptyBBox
+	}
+	lons := make([]float64, len(points))
+	var b = BBox{South: 90, North: -90}
+	for i, p := range points {
+		b.South = math.Min(b.South, p.Lat())
+		b.North = math.Max(b.North, p.Lat())
+		lons[i] = p.Lon()
+	}
+	sort.Float64s(lons)
+	// ищем наибольший промежуток между соседними долготами: область является его дополнением
+	b.West, b.East = lons[0], lons[len(lons)-1]
+	gap := lons[0] + 360 - lons[len(lons)-1] // промежуток через меридиан ±180°
+	for i := 1; i < len(lons); i++ {
+		if d := lons[i] - lons[i-1]; d > gap {
+			gap = d
+			b.West, b.East = lons[i], lons[i-1]
+		}
+	}
+	return b
+}
+
+// IsEmpty возвращает true, если область пустая.
+func (b BBox) IsEmpty() bool {
+	return math.IsNaN(b.South) || math.IsNaN(b.West) || math.IsNaN(b.North) || math.IsNaN(b.East) ||
+		b.South > b.North
+}
+
+// CrossesAntimeridian возвращает true, если область пересекает меридиан ±180°.
+func (b BBox) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+// lonSpan возвращает ширину области по долготе в градусах.
+func (b BBox) lonSpan() float64 {
+	if b.West <= b.East {
+		return b.East - b.West
+	}
+	return b.East - b.West + 360
+}
+
+// Center возвращает центр области.
+func (b BBox) Center() Point {
+	if b.IsEmpty() {
+		return NaNPoint
+	}
+	return Point{(b.South + b.North) / 2, normalizeLon(b.West + b.lonSpan()/2)}
+}
+
+// containsLon возвращает true, если долгота попадает в границы области.
+func (b BBox) containsLon(lon float64) bool {
+	if b.West <= b.East {
+		return lon >= b.West && lon <= b.East
+	}
+	return lon >= b.West || lon <= b.East
+}
+
+// Contains возвращает true, если точка находится внутри области или на ее границе.
+func (b BBox) Contains(p Point) bool {
+	if b.IsEmpty() {
+		return false
+	}
+	return p.Lat() >= b.South && p.Lat() <= b.North && b.containsLon(p.Lon())
+}
+
+// Intersects возвращает true, если области имеют общие точки.
+func (b BBox) Intersects(b2 BBox) bool {
+	if b.IsEmpty() || b2.IsEmpty() {
+		return false
+	}
+	if b.South > b2.North || b2.South > b.North {
+		return false
+	}
+	return b.containsLon(b2.West) || b2.containsLon(b.West)
+}
+
+// lonOffset возвращает расстояние по долготе в градусах при движении на восток от from до to.
+func lonOffset(from, to float64) float64 {
+	d := math.Mod(to-from, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+// distance возвращает расстояние в километрах от точки до ближайшей к ней точки области.
+func (b BBox) distance(p Point) float64 {
+	if b.IsEmpty() {
+		return math.Inf(1)
+	}
+	if b.Contains(p) {
+		return 0
+	}
+	// на параллели расстояние растет с разницей долгот, поэтому ближайшая точка границы лежит
+	// на долготе p или на ближайшем к ней меридиане области
+	lon := p.Lon()
+	if !b.containsLon(lon) {
+		if lonOffset(lon, b.West) < lonOffset(b.East, lon) {
+			lon = b.West
+		} else {
+			lon = b.East
+		}
+	}
+	d := math.Min(p.Distance(Point{b.South, lon}), p.Distance(Point{b.North, lon}))
+	// на меридиане ближайшая точка большого круга может оказаться внутри отрезка, иначе
+	// минимум достигается на одном из концов, которые уже учтены выше или проверяются здесь
+	lat := p.Lat() * (math.Pi / 180.0)
+	for _, lon := range []float64{b.West, b.East} {
+		dLon := (lon - p.Lon()) * (math.Pi / 180.0)
+		m := math.Atan2(math.Sin(lat), math.Cos(lat)*math.Cos(dLon)) * (180.0 / math.Pi)
+		m = math.Max(b.South, math.Min(b.North, m))
+		for _, lat := range []float64{m, b.South, b.North} {
+			d = math.Min(d, p.Distance(Point{lat, lon}))
+		}
+	}
+	return d
+}
+
+// Union возвращает минимальную область, содержащую обе области.
+func (b BBox) Union(b2 BBox) BBox {
+	if b.IsEmpty() {
+		return b2
+	}
+	if b2.IsEmpty() {
+		return b
+	}
+	var u = BBox{South: math.Min(b.South, b2.South), North: math.Max(b.North, b2.North)}
+	span1, span2 := b.lonSpan(), b2.lonSpan()
+	switch {
+	case lonOffset(b.West, b2.West)+span2 <= span1: // b2 внутри b по долготе
+		u.West, u.East = b.West, b.East
+	case lonOffset(b2.West, b.West)+span1 <= span2: // b внутри b2 по долготе
+		u.West, u.East = b2.West, b2.East
+	default:
+		// выбираем наименьший из двух вариантов охвата: с запада b до востока b2 или наоборот
+		east := lonOffset(b.West, b2.West) + span2
+		west := lonOffset(b2.West, b.West) + span1
+		if east <= west {
+			u.West, u.East = b.West, b2.East
+		} else {
+			u.West, u.East = b2.West, b.East
+		}
+		if math.Min(east, west) >= 360 {
+			u.West, u.East = -180, 180
+		}
+	}
+	return u
+}
+
+// Extend возвращает минимальную область, содержащую исходную область и указанную точку.
+func (b BBox) Extend(p Point) BBox {
+	return b.Union(BBox{p.Lat(), p.Lon(), p.Lat(), p.Lon()})
+}
+
+// Expand возвращает область, расширенную во все стороны на указанное расстояние в километрах.
+// Если расширенная область захватывает полюс, то она охватывает все долготы.
+func (b BBox) Expand(distance float64) BBox {
+	if b.IsEmpty() {
+		return b
+	}
+	delta := distance / erath_radius // угловое расстояние в радианах
+	dLat := delta * (180.0 / math.Pi)
+	e := BBox{South: b.South - dLat, West: b.West, North: b.North + dLat, East: b.East}
+	if e.South <= -90 || e.North >= 90 {
+		e.South, e.North = math.Max(e.South, -90), math.Min(e.North, 90)
+		e.West, e.East = -180, 180
+		return e
+	}
+	// приращение долготы зависит от широты: берем самую удаленную от экватора границу
+	lat := math.Max(math.Abs(e.South), math.Abs(e.North)) * (math.Pi / 180.0)
+	sinLon := math.Sin(delta) / math.Cos(lat)
+	if sinLon >= 1 || b.lonSpan()+2*math.Asin(sinLon)*(180.0/math.Pi) >= 360 {
+		e.West, e.East = -180, 180
+		return e
+	}
+	dLon := math.Asin(sinLon) * (180.0 / math.Pi)
+	e.West, e.East = normalizeLon(b.West-dLon), normalizeLon(b.East+dLon)
+	return e
+}
+
+// String возвращает строковое представление области.
+func (b BBox) String() string {
+	return fmt.Sprintf("[%f,%f,%f,%f]", b.South, b.West, b.North, b.East)
+}
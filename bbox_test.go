@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBBox(t *testing.T) {
+	b := NewBBox(NewPoint(55.5, 37.3), NewPoint(55.9, 37.9), NewPoint(55.7, 37.6))
+	if b != (BBox{55.5, 37.3, 55.9, 37.9}) {
+		t.Errorf("bad bbox: %v", b)
+	}
+	if !b.Contains(NewPoint(55.75, 37.61)) || b.Contains(NewPoint(59.9, 30.3)) {
+		t.Error("bad contains")
+	}
+	if b.CrossesAntimeridian() {
+		t.Error("unexpected antimeridian crossing")
+	}
+	if c := b.Center(); c.Distance(NewPoint(55.7, 37.6)) > 0.001 {
+		t.Errorf("bad center: %v", c)
+	}
+	if !EmptyBBox.IsEmpty() || !NewBBox().IsEmpty() {
+		t.Error("bad empty bbox")
+	}
+	if EmptyBBox.Contains(NewPoint(0, 0)) {
+		t.Error("empty bbox contains point")
+	}
+}
+
+func TestBBoxAntimeridian(t *testing.T) {
+	// Чукотка: Анадырь и Уэлен по разные стороны от меридиана 180°
+	chukotka := NewBBox(NewPoint(64.73, 177.51), NewPoint(66.16, -169.81), NewPoint(69.7, 170.3))
+	if !chukotka.CrossesAntimeridian() {
+		t.Fatalf("expected antimeridian crossing: %v", chukotka)
+	}
+	if chukotka.West != 170.3 || chukotka.East != -169.81 {
+		t.Errorf("bad bbox: %v", chukotka)
+	}
+	if !chukotka.Contains(NewPoint(65, 180)) || !chukotka.Contains(NewPoint(65, -175)) ||
+		chukotka.Contains(NewPoint(65, 0)) {
+		t.Error("bad contains")
+	}
+	if c := chukotka.Center(); math.Abs(c.Lon()-(-179.755)) > 1e-9 {
+		t.Errorf("bad center: %v", c)
+	}
+	alaska := BBox{South: 60, West: -170, North: 70, East: -140}
+	if !chukotka.Intersects(alaska) || !alaska.Intersects(chukotka) {
+		t.Error("expected intersection")
+	}
+	moscow := BBox{South: 55, West: 37, North: 56, East: 38}
+	if chukotka.Intersects(moscow) {
+		t.Error("unexpected intersection")
+	}
+	u := chukotka.Union(alaska)
+	if u.West != 170.3 || u.East != -140 || u.South != 60 || u.North != 70 {
+		t.Errorf("bad union: %v", u)
+	}
+	if u := chukotka.Union(BBox{65, 175, 66, 179}); u != chukotka {
+		t.Errorf("bad inner union: %v", u)
+	}
+	if u := moscow.Extend(NewPoint(55.5, 39)); u != (BBox{55, 37, 56, 39}) {
+		t.Errorf("bad extend: %v", u)
+	}
+}
+
+func TestBBoxExpand(t *testing.T) {
+	b := BBox{South: 60, West: 179.9, North: 60.1, East: -179.9}.Expand(100)
+	if !b.CrossesAntimeridian() {
+		t.Fatalf("expected antimeridian crossing: %v", b)
+	}
+	for _, bearing := range []float64{0, 90, 180, 270} {
+		for _, p := range []Point{{60, 179.9}, {60.1, -179.9}} {
+			if d := p.Destination(bearing, 99.9); !b.Contains(d) {
+				t.Errorf("%v not in %v", d, b)
+			}
+		}
+	}
+	if b := (BBox{89, 0, 89.5, 1}).Expand(200); b.North != 90 || b.West != -180 || b.East != 180 {
+		t.Errorf("bad polar expand: %v", b)
+	}
+}
@@ -0,0 +1,60 @@
+package geo
+
+// Noise обозначает точки, не попавшие ни в один кластер, в результате DBSCAN.
+const Noise = -1
+
+// DBSCAN разбивает точки на кластеры по плотности (алгоритм DBSCAN). Точка считается
+// внутренней, если в радиусе eps метров от нее (включая ее саму) находится не меньше minPoints
+// точек; кластер образуют внутренние точки, достижимые друг из друга через соседей, и их
+// граничные соседи. Возвращает номер кластера для каждой точки (начиная с 0) или Noise и
+// количество найденных кластеров.
+func DBSCAN(points []Point, eps float64, minPoints int) ([]int, int) {
+	items := make([]IndexItem, len(points))
+	for i, p := range points {
+		items[i] = IndexItem{Point: p, Value: i}
+	}
+	index := NewIndex(items...)
+	neighbors := func(i int) []int {
+		found := index.Within(points[i], eps/1000)
+		result := make([]int, len(found))
+		for j, item := range found {
+			result[j] = item.Value.(int)
+		}
+		return result
+	}
+	const unvisited = -2
+	labels := make([]int, len(points))
+	for i := range labels {
+		labels[i] = unvisited
+	}
+	var clusters int
+	for i := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		seeds := neighbors(i)
+		if len(seeds) < minPoints {
+			labels[i] = Noise // может позже оказаться граничной точкой другого кластера
+			continue
+		}
+		cluster := clusters
+		clusters++
+		labels[i] = cluster
+		// расширяем кластер через соседей внутренних точек
+		for len(seeds) > 0 {
+			j := seeds[len(seeds)-1]
+			seeds = seeds[:len(seeds)-1]
+			if labels[j] == Noise {
+				labels[j] = cluster // граничная точка
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = cluster
+			if next := neighbors(j); len(next) >= minPoints {
+				seeds = append(seeds, next...)
+			}
+		}
+	}
+	return labels, clusters
+}
@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDBSCAN(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var points []Point
+	// два плотных скопления в 100 м и шум
+	centers := []Point{{55.7558, 37.6173}, {55.7658, 37.6173}}
+	for _, c := range centers {
+		for i := 0; i < 50; i++ {
+			points = append(points, c.Destination(r.Float64()*360, r.Float64()*0.1))
+		}
+	}
+	noise := []Point{{55.7, 37.5}, {55.8, 37.7}, {0, 0}}
+	points = append(points, noise...)
+	labels, n := DBSCAN(points, 50, 5)
+	if n != 2 {
+		t.Fatalf("found %d clusters", n)
+	}
+	for i := range centers {
+		for j := i * 50; j < (i+1)*50; j++ {
+			if labels[j] != labels[i*50] || labels[j] == Noise {
+				t.Errorf("point %d: label %d", j, labels[j])
+			}
+		}
+	}
+	if labels[0] == labels[50] {
+		t.Error("clusters are merged")
+	}
+	for j := 100; j < len(points); j++ {
+		if labels[j] != Noise {
+			t.Errorf("noise point %d: label %d", j, labels[j])
+		}
+	}
+	// граничная точка присоединяется к кластеру, но не расширяет его
+	line := []Point{{0, 0}}
+	for i := 1; i < 6; i++ {
+		line = append(line, line[0].Destination(90, float64(i)*0.01))
+	}
+	labels, n = DBSCAN(line, 15, 3)
+	if n != 1 {
+		t.Fatalf("line: %d clusters: %v", n, labels)
+	}
+	for i, label := range labels {
+		if label != 0 {
+			t.Errorf("line point %d: label %d", i, label)
+		}
+	}
+	labels, n = DBSCAN(line, 5, 2)
+	if n != 0 {
+		t.Errorf("sparse line: %d clusters: %v", n, labels)
+	}
+	if labels, n := DBSCAN(nil, 10, 2); len(labels) != 0 || n != 0 {
+		t.Errorf("bad empty result: %v %d", labels, n)
+	}
+}
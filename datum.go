@@ -0,0 +1,70 @@
+package geo
+
+import "math"
+
+// Helmert описывает 7-параметрическое преобразование Гельмерта между геоцентрическими
+// системами координат. Знаки углов поворота соответствуют формулам ГОСТ Р 51794-2008
+// (поворот системы координат, coordinate frame rotation).
+type Helmert struct {
+	DX, DY, DZ float64 // линейные смещения в метрах
+	RX, RY, RZ float64 // углы поворота вокруг осей в угловых секундах
+	M          float64 // масштабный коэффициент в миллионных долях (ppm)
+}
+
+// Apply применяет преобразование к геоцентрическим координатам в метрах.
+func (t Helmert) Apply(x, y, z float64) (float64, float64, float64) {
+	const arcsec = math.Pi / 180.0 / 3600
+	rx, ry, rz := t.RX*arcsec, t.RY*arcsec, t.RZ*arcsec
+	m := 1 + t.M*1e-6
+	return m*(x+rz*y-ry*z) + t.DX,
+		m*(-rz*x+y+rx*z) + t.DY,
+		m*(ry*x-rx*y+z) + t.DZ
+}
+
+// Inverse возвращает обратное преобразование. Параметры меняют знак, что при малых углах
+// поворота дает погрешность меньше миллиметра.
+func (t Helmert) Inverse() Helmert {
+	return Helmert{-t.DX, -t.DY, -t.DZ, -t.RX, -t.RY, -t.RZ, -t.M}
+}
+
+// Datum описывает геодезическую систему координат: эллипсоид и параметры преобразования
+// в WGS84.
+type Datum struct {
+	Name      string
+	Ellipsoid Ellipsoid
+	ToWGS84   Helmert // преобразование геоцентрических координат в WGS84
+}
+
+// Поддерживаемые системы координат. Источник параметров преобразования указан для каждой
+// системы.
+var (
+	// DatumWGS84 - World Geodetic System 1984, система координат GPS.
+	DatumWGS84 = Datum{Name: "WGS84", Ellipsoid: WGS84}
+	// DatumPZ9011 - ПЗ-90.11, система координат ГЛОНАСС. Параметры преобразования в WGS84
+	// взяты из ГОСТ 32453-2017.
+	DatumPZ9011 = Datum{Name: "PZ-90.11", Ellipsoid: PZ90, ToWGS84: Helmert{
+		DX: -0.013, DY: 0.106, DZ: 0.022,
+		RX: -0.00230, RY: 0.00354, RZ: -0.00421,
+		M: -0.008,
+	}}
+	// DatumSK42 - СК-42 (Пулково 1942) на эллипсоиде Красовского. Параметры преобразования
+	// в WGS84 взяты из ГОСТ Р 51794-2008.
+	DatumSK42 = Datum{Name: "SK-42", Ellipsoid: Krassowsky, ToWGS84: Helmert{
+		DX: 23.57, DY: -140.95, DZ: -79.8,
+		RX: 0, RY: -0.35, RZ: -0.79,
+		M: -0.22,
+	}}
+)
+
+// Transform пересчитывает координаты точки из одной системы координат в другую. Высота точки
+// над эллипсоидом считается равной нулю; ее изменение при пересчете не возвращается.
+func (p Point) Transform(from, to Datum) Point {
+	if from == to {
+		return p
+	}
+	x, y, z := from.Ellipsoid.toECEF(p, 0)
+	x, y, z = from.ToWGS84.Apply(x, y, z)
+	x, y, z = to.ToWGS84.Inverse().Apply(x, y, z)
+	p, _ = to.Ellipsoid.fromECEF(x, y, z)
+	return p
+}
@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestECEF(t *testing.T) {
+	// (0, 0) на экваторе находится на оси X на расстоянии большой полуоси
+	x, y, z := WGS84.toECEF(Point{0, 0}, 0)
+	if x != WGS84.A || math.Abs(y) > 1e-9 || math.Abs(z) > 1e-9 {
+		t.Errorf("bad ECEF: %f %f %f", x, y, z)
+	}
+	x, y, z = WGS84.toECEF(Point{90, 0}, 100)
+	if math.Abs(x) > 1e-9 || math.Abs(z-WGS84.B()-100) > 1e-6 {
+		t.Errorf("bad pole ECEF: %f %f %f", x, y, z)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		h := r.Float64()*20000 - 1000
+		p2, h2 := WGS84.fromECEF(WGS84.toECEF(p, h))
+		if p.Distance(p2) > 1e-9 || math.Abs(h-h2) > 1e-6 {
+			t.Errorf("%v %f: %v %f", p, h, p2, h2)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	// контрольные значения рассчитаны по формулам ГОСТ Р 51794-2008 (переход через
+	// геоцентрические координаты без приближения малых углов) с параметрами из стандартов,
+	// указанными для каждой системы. Точность 0.1 м позволяет отличить, например, параметры
+	// ГОСТ Р 51794-2001 для СК-42, которые дают в Москве смещение около 0.7 м.
+	for _, test := range []struct {
+		from    Datum
+		p, want Point
+	}{
+		{DatumSK42, Point{55.75, 37.6166666666667}, Point{55.7500426, 37.6147925}},
+		{DatumPZ9011, Point{55.75, 37.6166666666667}, Point{55.75000127, 37.61666944}},
+	} {
+		p := test.p.Transform(test.from, DatumWGS84)
+		if d := p.Distance(test.want); d > 0.0001 {
+			t.Errorf("%s: %v, want %v (%.3f m)", test.from.Name, p, test.want, d*1000)
+		}
+		if d := p.Transform(DatumWGS84, test.from).Distance(test.p); d > 1e-6 {
+			t.Errorf("%s: bad inverse transform: %f km", test.from.Name, d)
+		}
+	}
+	moscow := Point{55.7539, 37.6208}
+	if p := moscow.Transform(DatumSK42, DatumSK42); p != moscow {
+		t.Errorf("identity transform changed point: %v", p)
+	}
+	// преобразование через WGS84 между двумя другими системами
+	sk42 := moscow.Transform(DatumWGS84, DatumSK42)
+	p := sk42.Transform(DatumSK42, DatumPZ9011).Transform(DatumPZ9011, DatumWGS84)
+	if d := p.Distance(moscow); d > 1e-6 {
+		t.Errorf("bad chained transform: %f km", d)
+	}
+}
+
+func TestGaussKruger(t *testing.T) {
+	// на осевом меридиане зоны X равна длине дуги меридиана на эллипсоиде Красовского
+	for _, test := range []struct {
+		lat, lon float64
+		x, y     float64
+	}{
+		{55, 39, 6097337.19, 7500000},
+		{60, 33, 6654189.09, 6500000},
+		{0, 9, 0, 2500000},
+	} {
+		p := Point{test.lat, test.lon}.Transform(DatumSK42, DatumWGS84)
+		g, err := p.GaussKruger(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(g.X-test.x) > 0.01 || math.Abs(g.Y-test.y) > 0.01 {
+			t.Errorf("%v: %v", test, g)
+		}
+	}
+	moscow := Point{55.7539, 37.6208}
+	g, err := moscow.GaussKruger(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Zone() != 7 {
+		t.Errorf("bad zone: %d", g.Zone())
+	}
+	// та же точка в соседней зоне
+	g6, err := moscow.GaussKruger(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g6.Zone() != 6 || g6.Y-6e6 < 500000 {
+		t.Errorf("bad neighbour zone: %v", g6)
+	}
+	for _, g := range []GaussKruger{g, g6} {
+		p, err := g.Point()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := p.Distance(moscow); d > 1e-6 {
+			t.Errorf("%v: bad inverse %v (%f km)", g, p, d)
+		}
+	}
+	// западное полушарие: зоны продолжают нумерацию от Гринвича на восток
+	if g, _ := (Point{64.8, -147.7}).GaussKruger(0); g.Zone() != 36 {
+		t.Errorf("bad western zone: %v", g)
+	}
+	if _, err := moscow.GaussKruger(61); err == nil {
+		t.Error("expected zone error")
+	}
+	if _, err := (GaussKruger{X: 6e6, Y: 500000}).Point(); err == nil {
+		t.Error("expected error for zone 0")
+	}
+}
@@ -0,0 +1,62 @@
+package geo
+
+import "math"
+
+// Ellipsoid описывает параметры референц-эллипсоида: большую полуось в метрах и сжатие.
+type Ellipsoid struct {
+	A float64 // большая полуось (экваториальный радиус) в метрах
+	F float64 // сжатие (flattening)
+}
+
+// WGS84 описывает эллипсоид World Geodetic System 1984, используемый GPS.
+var WGS84 = Ellipsoid{A: 6378137, F: 1 / 298.257223563}
+
+// B возвращает малую (полярную) полуось эллипсоида в метрах.
+func (e Ellipsoid) B() float64 {
+	return e.A * (1 - e.F)
+}
+
+// E2 возвращает квадрат первого эксцентриситета эллипсоида.
+func (e Ellipsoid) E2() float64 {
+	return e.F * (2 - e.F)
+}
+
+// PZ90 описывает эллипсоид системы координат ПЗ-90 (Параметры Земли 1990), используемой ГЛОНАСС.
+var PZ90 = Ellipsoid{A: 6378136, F: 1 / 298.25784}
+
+// Krassowsky описывает эллипсоид Красовского, используемый в системе координат СК-42.
+var Krassowsky = Ellipsoid{A: 6378245, F: 1 / 298.3}
+
+// toECEF возвращает геоцентрические координаты (ECEF) в метрах для точки с высотой h в метрах
+// над эллипсоидом.
+func (e Ellipsoid) toECEF(p Point, h float64) (x, y, z float64) {
+	phi := p.Lat() * (math.Pi / 180.0)
+	lambda := p.Lon() * (math.Pi / 180.0)
+	sinPhi, cosPhi := math.Sincos(phi)
+	sinL, cosL := math.Sincos(lambda)
+	e2 := e.E2()
+	n := e.A / math.Sqrt(1-e2*sinPhi*sinPhi) // радиус кривизны первого вертикала
+	return (n + h) * cosPhi * cosL, (n + h) * cosPhi * sinL, (n*(1-e2) + h) * sinPhi
+}
+
+// fromECEF возвращает точку и высоту над эллипсоидом в метрах по геоцентрическим координатам.
+func (e Ellipsoid) fromECEF(x, y, z float64) (Point, float64) {
+	e2 := e.E2()
+	r := math.Hypot(x, y)
+	lambda := math.Atan2(y, x)
+	// широта уточняется итерациями, начиная с приближения Боуринга
+	phi := math.Atan2(z, r*(1-e2))
+	var n, h float64
+	for i := 0; i < 10; i++ {
+		sinPhi, cosPhi := math.Sincos(phi)
+		n = e.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+		h = r*cosPhi + z*sinPhi - n*(1-e2*sinPhi*sinPhi) // устойчиво и вблизи полюсов
+		next := math.Atan2(z, r*(1-e2*n/(n+h)))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return Point{phi * (180.0 / math.Pi), lambda * (180.0 / math.Pi)}, h
+}
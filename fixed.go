@@ -0,0 +1,158 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// FixedPoint описывает компактное представление координат точки (lat, lon) в виде целых
+// чисел: градусы, умноженные на 10^7. Занимает 8 байт вместо 16 у Point, а точность
+// представления лучше сантиметра.
+type FixedPoint [2]int32
+
+// fixedScale задает множитель для перевода градусов в целые числа.
+const fixedScale = 1e7
+
+// NaNFixedPoint описывает пустую точку. Соответствует NaNPoint.
+var NaNFixedPoint = FixedPoint{math.MinInt32, math.MinInt32}
+
+// Fixed возвращает компактное представление точки. Координаты округляются до 10^-7 градуса.
+// Для пустой точки или координат вне допустимого диапазона возвращается NaNFixedPoint.
+func (p Point) Fixed() FixedPoint {
+	if !(p[0] >= -90 && p[0] <= 90 && p[1] >= -180 && p[1] <= 180) {
+		return NaNFixedPoint
+	}
+	return FixedPoint{int32(math.Round(p[0] * fixedScale)), int32(math.Round(p[1] * fixedScale))}
+}
+
+// IsNaN возвращает true, если точка пустая.
+func (f FixedPoint) IsNaN() bool {
+	return f == NaNFixedPoint
+}
+
+// Point возвращает точку, соответствующую компактному представлению.
+func (f FixedPoint) Point() Point {
+	if f.IsNaN() {
+		return NaNPoint
+	}
+	return Point{f.Lat(), f.Lon()}
+}
+
+// Lat возвращает широту.
+func (f FixedPoint) Lat() float64 {
+	return float64(f[0]) / fixedScale
+}
+
+// Lon возвращает долготу.
+func (f FixedPoint) Lon() float64 {
+	return float64(f[1]) / fixedScale
+}
+
+// Distance возвращает расстояние между двумя точками в километрах.
+func (f FixedPoint) Distance(f2 FixedPoint) float64 {
+	return f.Point().Distance(f2.Point())
+}
+
+// String возвращает строковое представление точки.
+func (f FixedPoint) String() string {
+	return f.Point().String()
+}
+
+// grid возвращает координаты точки как беззнаковые числа, отсчитываемые от юго-западного
+// угла: долгота от 0 до 3.6*10^9, широта от 0 до 1.8*10^9.
+func (f FixedPoint) grid() (x, y uint32) {
+	return uint32(int64(f[1]) + 180*fixedScale), uint32(int64(f[0]) + 90*fixedScale)
+}
+
+// MortonKey возвращает ключ точки на кривой Мортона (Z-order): биты долготы и широты
+// чередуются. Точки, близкие по ключу, как правило находятся рядом.
+func (f FixedPoint) MortonKey() uint64 {
+	x, y := f.grid()
+	return interleave(x) | interleave(y)<<1
+}
+
+// interleave раздвигает биты числа, вставляя между ними нули.
+func interleave(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// HilbertKey возвращает ключ точки на кривой Гильберта. В отличие от кривой Мортона,
+// соседние по ключу точки всегда соседние и в пространстве, поэтому сортировка по этому ключу
+// лучше сохраняет локальность.
+func (f FixedPoint) HilbertKey() uint64 {
+	x, y := f.grid()
+	var d uint64
+	for s := uint32(1 << 31); s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		// поворачиваем квадрант
+		if ry == 0 {
+			if rx == 1 {
+				x, y = ^x, ^y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// ByHilbert позволяет сортировать точки по ключу на кривой Гильберта с помощью sort.Sort.
+type ByHilbert []FixedPoint
+
+func (p ByHilbert) Len() int           { return len(p) }
+func (p ByHilbert) Less(i, j int) bool { return p[i].HilbertKey() < p[j].HilbertKey() }
+func (p ByHilbert) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// MarshalJSON возвращает представление точки в формате JSON, совпадающее с Point.
+func (f FixedPoint) MarshalJSON() ([]byte, error) {
+	return f.Point().MarshalJSON()
+}
+
+// UnmarshalJSON восстанавливает точку из JSON в любом формате, поддерживаемом Point.
+func (f *FixedPoint) UnmarshalJSON(data []byte) error {
+	var p Point
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*f = p.Fixed()
+	return nil
+}
+
+// MarshalBinary возвращает бинарное представление точки: 8 байт - широта и долгота в виде
+// int32 (big-endian). Это представление используется так же и encoding/gob.
+func (f FixedPoint) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint32(data, uint32(f[0]))
+	binary.BigEndian.PutUint32(data[4:], uint32(f[1]))
+	return data, nil
+}
+
+// UnmarshalBinary восстанавливает точку из бинарного представления.
+func (f *FixedPoint) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errors.New("bad binary fixed point length")
+	}
+	lat, lon := int32(binary.BigEndian.Uint32(data)), int32(binary.BigEndian.Uint32(data[4:]))
+	fp := FixedPoint{lat, lon}
+	if !fp.IsNaN() && (lat < -90*fixedScale || lat > 90*fixedScale ||
+		lon < -180*fixedScale || lon > 180*fixedScale) {
+		return fmt.Errorf("bad binary fixed point: %v %v", lat, lon)
+	}
+	*f = fp
+	return nil
+}
@@ -0,0 +1,105 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestFixedPoint(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		f := p.Fixed()
+		if d := f.Point().Distance(p); d > 1e-5 { // 1 см
+			t.Fatalf("%v -> %v: %f km", p, f, d)
+		}
+		if f.Lat() != f.Point().Lat() || f.Lon() != f.Point().Lon() {
+			t.Fatalf("%v: bad coordinates", f)
+		}
+	}
+	for _, p := range []Point{{90, 180}, {-90, -180}, {0, 0}} {
+		if f := p.Fixed(); f.Point() != p {
+			t.Errorf("%v: %v", p, f.Point())
+		}
+	}
+	for _, p := range []Point{NaNPoint, {91, 0}, {0, 181}} {
+		if f := p.Fixed(); !f.IsNaN() || !f.Point().isNaN() {
+			t.Errorf("%v: expected NaN, got %v", p, f)
+		}
+	}
+	moscow, spb := Point{55.7558, 37.6173}, Point{59.9343, 30.3351}
+	if d := moscow.Fixed().Distance(spb.Fixed()); math.Abs(d-moscow.Distance(spb)) > 1e-5 {
+		t.Errorf("bad distance: %f", d)
+	}
+}
+
+func TestFixedPointKeys(t *testing.T) {
+	// точки сетки 4x4 в юго-западном углу: ключи - перестановка чисел от 0 до 15
+	cells := make(map[uint64][2]int32)
+	for x := int32(0); x < 4; x++ {
+		for y := int32(0); y < 4; y++ {
+			f := FixedPoint{-90*fixedScale + y, -180*fixedScale + x}
+			if m := f.MortonKey(); m != uint64(x&1|(y&1)<<1|(x&2)<<1|(y&2)<<2) {
+				t.Errorf("%d,%d: bad Morton key %d", x, y, m)
+			}
+			cells[f.HilbertKey()] = [2]int32{x, y}
+		}
+	}
+	// соседние по ключу Гильберта точки соседние и на плоскости
+	for key := uint64(0); key < 16; key++ {
+		c, ok := cells[key]
+		if !ok {
+			t.Fatalf("key %d not found: %v", key, cells)
+		}
+		if key > 0 {
+			prev := cells[key-1]
+			if math.Abs(float64(c[0]-prev[0]))+math.Abs(float64(c[1]-prev[1])) != 1 {
+				t.Errorf("keys %d and %d are not adjacent: %v %v", key-1, key, prev, c)
+			}
+		}
+	}
+	// сортировка: первая точка в юго-западном углу
+	points := []FixedPoint{Point{55.7558, 37.6173}.Fixed(), Point{-33.8568, 151.2153}.Fixed(),
+		Point{-89, -179}.Fixed(), Point{40.7128, -74.006}.Fixed()}
+	sort.Sort(ByHilbert(points))
+	if points[0] != (Point{-89, -179}).Fixed() || !sort.IsSorted(ByHilbert(points)) {
+		t.Errorf("bad sort: %v", points)
+	}
+}
+
+func TestFixedPointMarshal(t *testing.T) {
+	f := Point{55.7558, 37.6173}.Fixed()
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"lat":55.7558,"lon":37.6173}` {
+		t.Errorf("bad JSON: %s", data)
+	}
+	var f2 FixedPoint
+	if err := json.Unmarshal([]byte(`[37.6173,55.7558]`), &f2); err != nil || f2 != f {
+		t.Errorf("bad JSON decoding: %v %v", f2, err)
+	}
+	if err := json.Unmarshal([]byte(`null`), &f2); err != nil || !f2.IsNaN() {
+		t.Errorf("bad null decoding: %v %v", f2, err)
+	}
+	data, _ = f.MarshalBinary()
+	if len(data) != 8 {
+		t.Errorf("bad binary length: %d", len(data))
+	}
+	if err := f2.UnmarshalBinary(data); err != nil || f2 != f {
+		t.Errorf("bad binary decoding: %v %v", f2, err)
+	}
+	data, _ = NaNFixedPoint.MarshalBinary()
+	if err := f2.UnmarshalBinary(data); err != nil || !f2.IsNaN() {
+		t.Errorf("bad NaN binary decoding: %v %v", f2, err)
+	}
+	for _, data := range [][]byte{{1, 2, 3}, {0x7f, 0, 0, 0, 0, 0, 0, 0}} {
+		if err := f2.UnmarshalBinary(data); err == nil {
+			t.Errorf("%v: expected error", data)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// GaussKruger описывает прямоугольные координаты точки в проекции Гаусса-Крюгера в системе
+// координат СК-42 с шестиградусными зонами. Как принято в отечественной геодезии, X направлена
+// на север, а Y на восток и содержит номер зоны в миллионах метров: Y = N*1000000 + 500000 + y.
+type GaussKruger struct {
+	X float64 // северная координата в метрах
+	Y float64 // восточная координата в метрах с номером зоны
+}
+
+// gkProjection описывает проекцию Гаусса-Крюгера на эллипсоиде Красовского.
+var gkProjection = newTransverseMercator(Krassowsky)
+
+// ErrBadGaussKruger возвращается при некорректных координатах Гаусса-Крюгера.
+var ErrBadGaussKruger = errors.New("bad Gauss-Kruger coordinates")
+
+// gkZone возвращает номер шестиградусной зоны Гаусса-Крюгера для долготы. Зоны нумеруются
+// от Гринвича на восток от 1 до 60.
+func gkZone(lon float64) int {
+	return int(math.Floor(math.Mod(lon+360, 360)/6))%60 + 1
+}
+
+// gkMeridian возвращает долготу осевого меридиана зоны.
+func gkMeridian(zone int) float64 {
+	return normalizeLon(float64(zone)*6 - 3)
+}
+
+// GaussKruger возвращает координаты точки, заданной в WGS84, в проекции Гаусса-Крюгера
+// системы СК-42. Если zone равна 0, то номер зоны вычисляется по долготе точки; иначе
+// координаты вычисляются в указанной зоне, что бывает нужно для точек вблизи ее границы.
+func (p Point) GaussKruger(zone int) (GaussKruger, error) {
+	if !(p.Lat() >= -90 && p.Lat() <= 90) {
+		return GaussKruger{}, ErrBadLatitude
+	}
+	if !(p.Lon() >= -180 && p.Lon() <= 180) {
+		return GaussKruger{}, ErrBadLongitude
+	}
+	if zone < 0 || zone > 60 {
+		return GaussKruger{}, fmt.Errorf("bad Gauss-Kruger zone: %d", zone)
+	}
+	sk42 := p.Transform(DatumWGS84, DatumSK42)
+	if zone == 0 {
+		zone = gkZone(sk42.Lon())
+	}
+	y, x := gkProjection.forward(sk42, gkMeridian(zone), 1)
+	return GaussKruger{X: x, Y: float64(zone)*1e6 + 500000 + y}, nil
+}
+
+// Zone возвращает номер зоны, указанный в координате Y.
+func (g GaussKruger) Zone() int {
+	return int(math.Floor(g.Y / 1e6))
+}
+
+// Point возвращает точку в WGS84, соответствующую координатам Гаусса-Крюгера.
+func (g GaussKruger) Point() (Point, error) {
+	zone := g.Zone()
+	if zone < 1 || zone > 60 || math.IsNaN(g.X) {
+		return NaNPoint, ErrBadGaussKruger
+	}
+	y := g.Y - float64(zone)*1e6 - 500000
+	sk42 := gkProjection.inverse(y, g.X, gkMeridian(zone), 1)
+	p := sk42.Transform(DatumSK42, DatumWGS84)
+	return MakePoint(p.Lat(), p.Lon())
+}
+
+// String возвращает строковое представление координат в метрах, например
+// "X=6182125.17 Y=7413521.07".
+func (g GaussKruger) String() string {
+	return fmt.Sprintf("X=%.2f Y=%.2f", g.X, g.Y)
+}
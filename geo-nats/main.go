@@ -20,12 +20,6 @@ const (
 	natsServer     = "188.166.38.202:1234"
 )
 
-// Данные из прокси сервера
-type MsgFromEph struct {
-	Lon float64
-	Lat float64
-}
-
 func main() {
 	// TODO: по-хорошему, нужен, конечно, конфигурационный файл со всеми опциями
 	log.Println("Connecting to NATS...")
@@ -58,14 +52,14 @@ func main() {
 			log.Println("Error parse LBS:", err)
 			return
 		}
-		point := db.Find(req) // получаем точку по координатам
-		if math.IsNaN(point.Lat()) || math.IsNaN(point.Lon()) {
-			log.Println("Error searching LBS:", err)
+		result := db.Find(req) // получаем точку по координатам
+		if result.Err != nil {
+			log.Println("Error searching LBS:", result.Err)
 			// TODO: наверное, нужно отдавать пустой ответ
 			return
 		}
 		// отправляем ответ с данными
-		if err := nc.Publish(msg.Reply, []byte(point.String())); err != nil {
+		if err := nc.Publish(msg.Reply, []byte(result.Point.String())); err != nil {
 			log.Println("Error Publish ephemeridos:", err)
 		}
 	})
@@ -87,15 +81,18 @@ func main() {
 	cache := ublox.NewCache(client, profile, time.Minute*60, 200)
 	// добавляем подписку
 	ephSubs, err := nc.Subscribe(serviceNameEph, func(msg *nats.Msg) {
-		// разбор данных
-		var tmp MsgFromEph
+		// разбор данных: допустимость координат проверяется при разборе JSON
 		var point geo.Point
-		if err = json.Unmarshal(msg.Data, &tmp); err != nil {
+		if err := json.Unmarshal(msg.Data, &point); err != nil {
+			log.Println("Error point:", err)
 			//TODO: отправлять ошибку
 			return
 		}
-		point = geo.NewPoint(tmp.Lat, tmp.Lon) // создаем координаты
-		data, err := cache.Get(point)          // получаем данные из кеша
+		if math.IsNaN(point.Lat()) || math.IsNaN(point.Lon()) {
+			log.Println("Error point: empty")
+			return
+		}
+		data, err := cache.Get(point) // получаем данные из кеша
 		if err != nil {
 			log.Println("Error Get ephemeridos:", err)
 			// TODO: наверное, нужно отдавать пустой ответ
@@ -0,0 +1,219 @@
+package geo
+
+import "math"
+
+// GeodesicMode задает способ вычисления расстояния и азимутов между точками.
+type GeodesicMode int
+
+const (
+	// Spherical - быстрый расчет на сфере радиусом 6371 км (формула гаверсинусов).
+	Spherical GeodesicMode = iota
+	// Ellipsoidal - точный расчет на эллипсоиде WGS84 (формулы Винсенти).
+	Ellipsoidal
+)
+
+// Geodesic описывает решение обратной геодезической задачи между двумя точками.
+type Geodesic struct {
+	Distance       float64 // расстояние в километрах
+	ForwardAzimuth float64 // азимут из начальной точки на конечную в градусах [0, 360)
+	ReverseAzimuth float64 // азимут из конечной точки на начальную в градусах [0, 360)
+}
+
+// Geodesic возвращает расстояние между двумя точками в километрах, а так же прямой и обратный
+// азимуты. Способ вычисления задается параметром mode: Spherical совпадает с Distance,
+// Ellipsoidal вычисляет геодезическую линию на эллипсоиде WGS84.
+func (p Point) Geodesic(p2 Point, mode GeodesicMode) Geodesic {
+	if mode == Ellipsoidal {
+		return WGS84.Inverse(p, p2)
+	}
+	return Geodesic{
+		Distance:       p.Distance(p2),
+		ForwardAzimuth: sphericalAzimuth(p, p2),
+		ReverseAzimuth: sphericalAzimuth(p2, p),
+	}
+}
+
+// EllipsoidalDistance возвращает расстояние между двумя точками в километрах, вычисленное
+// на эллипсоиде WGS84 (формулы Винсенти, для почти антиподальных точек - метод Карни)
+// с точностью до долей миллиметра. Медленнее, чем Distance, и отличается от него до 0.5%.
+func (p Point) EllipsoidalDistance(p2 Point) float64 {
+	return WGS84.Inverse(p, p2).Distance
+}
+
+// Inverse решает обратную геодезическую задачу на эллипсоиде по формулам Винсенти:
+// возвращает расстояние в километрах, прямой и обратный азимуты.
+// Для почти антиподальных точек, где итерации не сходятся, азимут геодезической линии
+// находится делением пополам (см. inverseAntipodal).
+// https://www.movable-type.co.uk/scripts/latlong-vincenty.html
+func (e Ellipsoid) Inverse(p1, p2 Point) Geodesic {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+	)
+	f := e.F
+	phi1, phi2 := p1[0]*(math.Pi/180.0), p2[0]*(math.Pi/180.0)
+	L := (p2[1] - p1[1]) * (math.Pi / 180.0)
+	tanU1, tanU2 := (1-f)*math.Tan(phi1), (1-f)*math.Tan(phi2)
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	cosU2 := 1 / math.Sqrt(1+tanU2*tanU2)
+	sinU2 := tanU2 * cosU2
+
+	var (
+		sinLambda, cosLambda float64
+		sinSigma, cosSigma   float64
+		sigma, cosSqAlpha    float64
+		cos2SigmaM           float64
+		lambda               = L
+		converged            bool
+	)
+	for i := 0; i < maxIterations; i++ {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		x := cosU1*sinU2 - sinU1*cosU2*cosLambda
+		sinSigma = math.Sqrt(cosU2*sinLambda*cosU2*sinLambda + x*x)
+		if sinSigma == 0 {
+			return Geodesic{} // точки совпадают
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0 // экваториальная линия
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*f*sinAlpha*
+			(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda) > math.Pi+math.Abs(L) {
+			break // расходится: точки почти антиподальные
+		}
+		if math.Abs(lambda-prev) < epsilon {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return e.inverseAntipodal(p1, p2)
+	}
+	s := e.arcLength(cosSqAlpha, sigma, cos2SigmaM)
+	alpha1 := math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	alpha2 := math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)
+	return Geodesic{
+		Distance:       s / 1000,
+		ForwardAzimuth: normalizeAzimuth(alpha1 * (180.0 / math.Pi)),
+		ReverseAzimuth: normalizeAzimuth(alpha2*(180.0/math.Pi) + 180),
+	}
+}
+
+// arcLength возвращает длину геодезической линии в метрах по длине дуги sigma на
+// вспомогательной сфере (формулы Винсенти).
+func (e Ellipsoid) arcLength(cosSqAlpha, sigma, cos2SigmaM float64) float64 {
+	a, b := e.A, e.B()
+	sinSigma, cosSigma := math.Sincos(sigma)
+	uSq := cosSqAlpha * (a*a - b*b) / (b * b)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+	return b * A * (sigma - deltaSigma)
+}
+
+// inverseAntipodal решает обратную геодезическую задачу для почти антиподальных точек, где
+// не сходятся итерации Винсенти по долготе. Вместо этого подбирается начальный азимут:
+// разность долгот, которую проходит геодезическая линия до широты второй точки, монотонно
+// растет с азимутом, поэтому его можно найти делением пополам (метод Карни).
+// https://arxiv.org/abs/1109.4448
+func (e Ellipsoid) inverseAntipodal(p1, p2 Point) Geodesic {
+	f := e.F
+	beta := func(lat float64) float64 { // приведенная широта
+		return math.Atan((1 - f) * math.Tan(lat*(math.Pi/180.0)))
+	}
+	beta1, beta2 := beta(p1[0]), beta(p2[0])
+	// приводим к каноническому виду: |beta1| >= |beta2|, beta1 <= 0 и разность долгот в [0, pi]
+	swap := math.Abs(beta1) < math.Abs(beta2)
+	lon1, lon2 := p1[1], p2[1]
+	if swap {
+		beta1, beta2 = beta2, beta1
+		lon1, lon2 = lon2, lon1
+	}
+	latSign := beta1 > 0
+	if latSign {
+		beta1, beta2 = -beta1, -beta2
+	}
+	L := normalizeLon(lon2-lon1) * (math.Pi / 180.0)
+	lonSign := L < 0
+	if lonSign {
+		L = -L
+	}
+	sinBeta1, cosBeta1 := math.Sincos(beta1)
+	sinBeta2, cosBeta2 := math.Sincos(beta2)
+	// solve возвращает для начального азимута alpha1 разность долгот до второй точки,
+	// а так же параметры геодезической линии
+	solve := func(alpha1 float64) (lambda, sinAlpha0, cosAlpha2, sigma12, cos2SigmaM float64) {
+		sinAlpha1, cosAlpha1 := math.Sincos(alpha1)
+		sinAlpha0 = sinAlpha1 * cosBeta1
+		cosSqAlpha0 := 1 - sinAlpha0*sinAlpha0
+		// в каноническом виде линия пересекает широту второй точки, двигаясь на север
+		cosAlpha2 = math.Sqrt(math.Max(0, cosAlpha1*cosAlpha1*cosBeta1*cosBeta1+
+			(cosBeta2-cosBeta1)*(cosBeta2+cosBeta1))) // cos(alpha2) * cos(beta2)
+		sigma1 := math.Atan2(sinBeta1, cosAlpha1*cosBeta1)
+		sigma2 := math.Atan2(sinBeta2, cosAlpha2)
+		omega1 := math.Atan2(sinAlpha0*sinBeta1, cosAlpha1*cosBeta1)
+		omega2 := math.Atan2(sinAlpha0*sinBeta2, cosAlpha2)
+		sigma12 = math.Atan2(math.Max(0, math.Sin(sigma2-sigma1)), math.Cos(sigma2-sigma1))
+		omega12 := math.Atan2(math.Max(0, math.Sin(omega2-omega1)), math.Cos(omega2-omega1))
+		cos2SigmaM = math.Cos(2*sigma1 + sigma12)
+		C := f / 16 * cosSqAlpha0 * (4 + f*(4-3*cosSqAlpha0))
+		lambda = omega12 - (1-C)*f*sinAlpha0*(sigma12+C*math.Sin(sigma12)*
+			(cos2SigmaM+C*math.Cos(sigma12)*(-1+2*cos2SigmaM*cos2SigmaM)))
+		return
+	}
+	lo, hi := 0.0, math.Pi
+	for i := 0; i < 100 && hi-lo > 1e-15; i++ {
+		mid := (lo + hi) / 2
+		if lambda, _, _, _, _ := solve(mid); lambda < L {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	alpha1 := (lo + hi) / 2
+	_, sinAlpha0, cosAlpha2, sigma12, cos2SigmaM := solve(alpha1)
+	alpha2 := math.Atan2(sinAlpha0, cosAlpha2)
+	// возвращаемся от канонического вида к исходному
+	if lonSign {
+		alpha1, alpha2 = -alpha1, -alpha2
+	}
+	if latSign {
+		alpha1, alpha2 = math.Pi-alpha1, math.Pi-alpha2
+	}
+	if swap {
+		alpha1, alpha2 = alpha2+math.Pi, alpha1+math.Pi
+	}
+	return Geodesic{
+		Distance:       e.arcLength(1-sinAlpha0*sinAlpha0, sigma12, cos2SigmaM) / 1000,
+		ForwardAzimuth: normalizeAzimuth(alpha1 * (180.0 / math.Pi)),
+		ReverseAzimuth: normalizeAzimuth(alpha2*(180.0/math.Pi) + 180),
+	}
+}
+
+// sphericalAzimuth возвращает начальный азимут на сфере из точки p1 на точку p2 в градусах.
+func sphericalAzimuth(p1, p2 Point) float64 {
+	lat1 := p1[0] * (math.Pi / 180.0)
+	lat2 := p2[0] * (math.Pi / 180.0)
+	dLon := (p2[1] - p1[1]) * (math.Pi / 180.0)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return normalizeAzimuth(math.Atan2(y, x) * (180.0 / math.Pi))
+}
+
+// normalizeAzimuth приводит азимут в градусах к диапазону [0, 360).
+func normalizeAzimuth(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
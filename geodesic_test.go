@@ -0,0 +1,82 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// dms переводит градусы, минуты и секунды в десятичные градусы.
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+	return d + m/60 + s/3600
+}
+
+func TestGeodesicVincenty(t *testing.T) {
+	// пример из статьи Винсенти: Flinders Peak - Buninyong
+	flinders := NewPoint(dms(-37, 57, 3.72030), dms(144, 25, 29.52440))
+	buninyong := NewPoint(dms(-37, 39, 10.15610), dms(143, 55, 35.38390))
+	g := flinders.Geodesic(buninyong, Ellipsoidal)
+	if math.Abs(g.Distance-54.972271) > 1e-6 {
+		t.Errorf("bad distance: %f", g.Distance)
+	}
+	if math.Abs(g.ForwardAzimuth-dms(306, 52, 5.37)) > 1e-5 {
+		t.Errorf("bad forward azimuth: %f", g.ForwardAzimuth)
+	}
+	if math.Abs(g.ReverseAzimuth-dms(127, 10, 25.07)) > 1e-5 {
+		t.Errorf("bad reverse azimuth: %f", g.ReverseAzimuth)
+	}
+}
+
+func TestGeodesicModes(t *testing.T) {
+	moscow := NewPoint(55.7558, 37.6173)
+	murmansk := NewPoint(68.9585, 33.0827)
+	s := moscow.Geodesic(murmansk, Spherical)
+	e := moscow.Geodesic(murmansk, Ellipsoidal)
+	if s.Distance != moscow.Distance(murmansk) {
+		t.Errorf("spherical mode differs from Distance: %f", s.Distance)
+	}
+	if d := math.Abs(s.Distance-e.Distance) / e.Distance; d > 0.005 || d == 0 {
+		t.Errorf("unexpected difference between modes: %f", d)
+	}
+	if math.Abs(s.ForwardAzimuth-e.ForwardAzimuth) > 0.5 {
+		t.Errorf("azimuths differ: %f %f", s.ForwardAzimuth, e.ForwardAzimuth)
+	}
+	if g := moscow.Geodesic(moscow, Ellipsoidal); g.Distance != 0 {
+		t.Errorf("distance to itself: %f", g.Distance)
+	}
+	// почти антиподальные точки: не должно быть NaN
+	if g := NewPoint(0, 0).Geodesic(NewPoint(0.5, 179.7), Ellipsoidal); math.IsNaN(g.Distance) {
+		t.Error("NaN distance for antipodal points")
+	}
+}
+
+func TestGeodesicAntipodal(t *testing.T) {
+	// пример из статьи Карни "Algorithms for geodesics", где итерации Винсенти не сходятся
+	p1, p2 := NewPoint(-30, 0), NewPoint(29.9, 179.8)
+	for _, test := range []struct {
+		p1, p2           Point
+		forward, reverse float64
+	}{
+		{p1, p2, 161.890524736, 18.090737246 + 180},
+		{p2, p1, 18.090737246 + 180, 161.890524736},
+		{NewPoint(30, 0), NewPoint(-29.9, 179.8), 180 - 161.890524736, 360 - 18.090737246},
+		{NewPoint(-30, 0), NewPoint(29.9, -179.8), 360 - 161.890524736, 180 - 18.090737246},
+	} {
+		g := test.p1.Geodesic(test.p2, Ellipsoidal)
+		if math.Abs(g.Distance-19989.832827610) > 1e-6 {
+			t.Errorf("%v - %v: bad distance: %.9f", test.p1, test.p2, g.Distance)
+		}
+		if math.Abs(g.ForwardAzimuth-test.forward) > 1e-6 {
+			t.Errorf("%v - %v: bad forward azimuth: %.9f", test.p1, test.p2, g.ForwardAzimuth)
+		}
+		if math.Abs(g.ReverseAzimuth-normalizeAzimuth(test.reverse)) > 1e-6 {
+			t.Errorf("%v - %v: bad reverse azimuth: %.9f", test.p1, test.p2, g.ReverseAzimuth)
+		}
+	}
+	// антиподальные точки на экваторе: кратчайший путь проходит через полюс
+	if g := NewPoint(0, 0).Geodesic(NewPoint(0, 180), Ellipsoidal); math.Abs(g.Distance-20003.931458) > 1e-6 {
+		t.Errorf("bad equatorial antipodal distance: %.9f", g.Distance)
+	}
+}
@@ -0,0 +1,195 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// geohashBase32 описывает алфавит, используемый для кодирования geohash.
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashMaxPrecision задает максимальную длину geohash: 12 символов (60 бит) соответствуют
+// ячейке размером в несколько сантиметров, большая длина не имеет смысла.
+const geohashMaxPrecision = 12
+
+// Geohash возвращает geohash точки с указанным количеством символов. Для precision меньше 1
+// возвращается пустая строка, а больше 12 - geohash длиной 12 символов.
+// https://en.wikipedia.org/wiki/Geohash
+func (p Point) Geohash(precision int) string {
+	if precision < 1 {
+		return ""
+	}
+	if precision > geohashMaxPrecision {
+		precision = geohashMaxPrecision
+	}
+	var (
+		hash               = make([]byte, 0, precision)
+		minLat, maxLat     = -90.0, 90.0
+		minLon, maxLon     = -180.0, 180.0
+		bits, bit      int = 0, 0
+		even               = true // четные биты кодируют долготу, нечетные - широту
+	)
+	for len(hash) < precision {
+		if even {
+			mid := (minLon + maxLon) / 2
+			if p.Lon() >= mid {
+				bits = bits<<1 | 1
+				minLon = mid
+			} else {
+				bits <<= 1
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if p.Lat() >= mid {
+				bits = bits<<1 | 1
+				minLat = mid
+			} else {
+				bits <<= 1
+				maxLat = mid
+			}
+		}
+		even = !even
+		if bit++; bit == 5 {
+			hash = append(hash, geohashBase32[bits])
+			bits, bit = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// GeohashBBox возвращает область, соответствующую geohash.
+func GeohashBBox(hash string) (BBox, error) {
+	if hash == "" {
+		return EmptyBBox, fmt.Errorf("bad geohash: %q", hash)
+	}
+	var b = BBox{South: -90, West: -180, North: 90, East: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		bits := strings.IndexByte(geohashBase32, hash[i])
+		if bits < 0 {
+			return EmptyBBox, fmt.Errorf("bad geohash: %q", hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			if even {
+				mid := (b.West + b.East) / 2
+				if bits&mask != 0 {
+					b.West = mid
+				} else {
+					b.East = mid
+				}
+			} else {
+				mid := (b.South + b.North) / 2
+				if bits&mask != 0 {
+					b.South = mid
+				} else {
+					b.North = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return b, nil
+}
+
+// DecodeGeohash возвращает центр области, соответствующей geohash.
+func DecodeGeohash(hash string) (Point, error) {
+	b, err := GeohashBBox(hash)
+	if err != nil {
+		return NaNPoint, err
+	}
+	return b.Center(), nil
+}
+
+// geohashCellSize возвращает размеры ячейки geohash в градусах широты и долготы.
+func geohashCellSize(precision int) (dLat, dLon float64) {
+	bits := 5 * precision
+	return 180 / math.Pow(2, float64(bits/2)), 360 / math.Pow(2, float64(bits-bits/2))
+}
+
+// GeohashNeighbors возвращает 8 соседних ячеек в порядке: север, северо-восток, восток,
+// юго-восток, юг, юго-запад, запад, северо-запад. Соседние ячейки за полюсом не существуют и
+// возвращаются пустой строкой. При пересечении меридиана ±180° соседи берутся с другой стороны.
+func GeohashNeighbors(hash string) ([8]string, error) {
+	var neighbors [8]string
+	b, err := GeohashBBox(hash)
+	if err != nil {
+		return neighbors, err
+	}
+	c := b.Center()
+	dLat, dLon := b.North-b.South, b.East-b.West
+	for i, d := range [8][2]float64{
+		{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+	} {
+		lat := c.Lat() + d[0]*dLat
+		if lat > 90 || lat < -90 {
+			continue
+		}
+		neighbors[i] = Point{lat, normalizeLon(c.Lon() + d[1]*dLon)}.Geohash(len(hash))
+	}
+	return neighbors, nil
+}
+
+// geohashMaxCover ограничивает количество ячеек, перебираемых при покрытии области.
+const geohashMaxCover = 1 << 16
+
+// GeohashCoverBBox возвращает список ячеек geohash с указанным количеством символов, которые
+// вместе полностью покрывают область. Precision больше 12 считается равным 12. Если при
+// указанной точности область покрывает слишком много ячеек (больше 65536), то точность
+// уменьшается до подходящей, поэтому длина возвращаемых geohash может быть меньше precision.
+func GeohashCoverBBox(b BBox, precision int) []string {
+	return geohashCover(b, precision, nil)
+}
+
+// GeohashCoverCircle возвращает список ячеек geohash с указанным количеством символов, которые
+// вместе полностью покрывают круг с центром в точке center и радиусом radius в километрах.
+// Точность ограничивается так же, как в GeohashCoverBBox.
+func GeohashCoverCircle(center Point, radius float64, precision int) []string {
+	b := NewBBox(center).Expand(radius)
+	return geohashCover(b, precision, func(cell BBox) bool {
+		return cell.distance(center) <= radius
+	})
+}
+
+// geohashCover перебирает все ячейки, пересекающиеся с областью, и возвращает те из них,
+// для которых filter возвращает true (или все, если filter не задан).
+func geohashCover(b BBox, precision int, filter func(BBox) bool) []string {
+	if b.IsEmpty() || precision < 1 {
+		return nil
+	}
+	if precision > geohashMaxPrecision {
+		precision = geohashMaxPrecision
+	}
+	var dLat, dLon, south, north, west, cols float64
+	for ; ; precision-- {
+		dLat, dLon = geohashCellSize(precision)
+		// количество ячеек по широте и долготе, включая частично попадающие в область
+		south = math.Floor((b.South + 90) / dLat)
+		north = math.Min(math.Floor((b.North+90)/dLat), 180/dLat-1)
+		west = math.Floor((b.West + 180) / dLon)
+		cols = math.Floor((b.West+b.lonSpan()+180)/dLon) - west + 1
+		if total := 360 / dLon; cols > total {
+			cols = total
+		}
+		if (north-south+1)*cols <= geohashMaxCover || precision == 1 {
+			break
+		}
+	}
+	var hashes []string
+	for row := south; row <= north; row++ {
+		for col := west; col < west+cols; col++ {
+			cell := BBox{
+				South: row*dLat - 90,
+				West:  math.Mod(col*dLon, 360) - 180,
+				North: (row+1)*dLat - 90,
+			}
+			cell.East = cell.West + dLon
+			if filter != nil && !filter(cell) {
+				continue
+			}
+			hashes = append(hashes, cell.Center().Geohash(precision))
+		}
+	}
+	return hashes
+}
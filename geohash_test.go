@@ -0,0 +1,130 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeohash(t *testing.T) {
+	if h := NewPoint(57.64911, 10.40744).Geohash(11); h != "u4pruydqqvj" {
+		t.Errorf("bad geohash: %s", h)
+	}
+	p, err := DecodeGeohash("ezs42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(p.Lat()-42.605) > 0.01 || math.Abs(p.Lon()-(-5.603)) > 0.01 {
+		t.Errorf("bad decoded point: %v", p)
+	}
+	if _, err := DecodeGeohash("ezs4a"); err == nil {
+		t.Error("expected error for bad geohash")
+	}
+	moscow := NewPoint(55.7558, 37.6173)
+	for precision := 1; precision <= 12; precision++ {
+		h := moscow.Geohash(precision)
+		b, err := GeohashBBox(h)
+		if err != nil || !b.Contains(moscow) {
+			t.Errorf("%s: bbox %v does not contain point", h, b)
+		}
+	}
+	for _, precision := range []int{-1, 0} {
+		if h := moscow.Geohash(precision); h != "" {
+			t.Errorf("%d: unexpected geohash %q", precision, h)
+		}
+	}
+	if h := moscow.Geohash(100); h != moscow.Geohash(12) {
+		t.Errorf("geohash is not clamped: %q", h)
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	neighbors, err := GeohashNeighbors("ucfv0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GeohashBBox("ucfv0")
+	for i, h := range neighbors {
+		nb, err := GeohashBBox(h)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !nb.Intersects(b) || h == "ucfv0" {
+			t.Errorf("%d: %s is not a neighbor", i, h)
+		}
+	}
+	if neighbors[0] != "ucfv2" || neighbors[4] != "ucfub" || neighbors[2] != "ucfv1" {
+		t.Errorf("bad neighbors: %v", neighbors)
+	}
+	// ячейка у меридиана 180°: восточный сосед с другой стороны
+	h := NewPoint(65, 179.99).Geohash(4)
+	neighbors, _ = GeohashNeighbors(h)
+	if p, _ := DecodeGeohash(neighbors[2]); p.Lon() > 0 {
+		t.Errorf("bad eastern neighbor across antimeridian: %v", p)
+	}
+	// ячейка у полюса: соседей на севере нет
+	neighbors, _ = GeohashNeighbors(NewPoint(89.99, 0).Geohash(3))
+	if neighbors[0] != "" || neighbors[1] != "" || neighbors[7] != "" || neighbors[4] == "" {
+		t.Errorf("bad polar neighbors: %v", neighbors)
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	b := BBox{South: 55.5, West: 37.3, North: 55.9, East: 37.9}
+	hashes := GeohashCoverBBox(b, 4)
+	if len(hashes) == 0 {
+		t.Fatal("empty cover")
+	}
+	covered := func(hashes []string, p Point) bool {
+		for _, h := range hashes {
+			cell, _ := GeohashBBox(h)
+			if cell.Contains(p) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range []Point{{55.5, 37.3}, {55.9, 37.9}, {55.7, 37.6}, {55.5, 37.9}} {
+		if !covered(hashes, p) {
+			t.Errorf("%v is not covered by %v", p, hashes)
+		}
+	}
+	// круг через меридиан 180°
+	center := NewPoint(65, 179.9)
+	hashes = GeohashCoverCircle(center, 50, 4)
+	for _, bearing := range []float64{0, 45, 90, 135, 180, 225, 270, 315} {
+		if p := center.Destination(bearing, 49); !covered(hashes, p) {
+			t.Errorf("%v is not covered by %v", p, hashes)
+		}
+	}
+	if all := GeohashCoverBBox(NewBBox(center).Expand(50), 4); len(hashes) >= len(all) {
+		t.Errorf("circle cover is not smaller than bbox cover: %d >= %d", len(hashes), len(all))
+	}
+	// на высоких широтах ячейки, ближайшие к центру вне его параллели, не должны теряться
+	for _, center := range []Point{{70, 10}, {80, 10}, {-85, -170}} {
+		hashes = GeohashCoverCircle(center, 1000, 3)
+		for bearing := 0.0; bearing < 360; bearing += 0.5 {
+			if p := center.Destination(bearing, 999); !covered(hashes, p) {
+				t.Errorf("%v is not covered by circle around %v", p, center)
+				break
+			}
+		}
+	}
+	// точность ограничена 12 символами
+	p := NewPoint(55.75, 37.62)
+	hashes = GeohashCoverBBox(NewBBox(p), 20)
+	if len(hashes) != 1 || hashes[0] != p.Geohash(12) {
+		t.Errorf("bad cover with large precision: %v", hashes)
+	}
+	// для большой области точность уменьшается
+	hashes = GeohashCoverBBox(BBox{South: -90, West: -180, North: 90, East: 180}, 12)
+	if len(hashes) == 0 || len(hashes) > geohashMaxCover || len(hashes[0]) >= 12 {
+		t.Errorf("bad cover of the whole world: %d cells", len(hashes))
+	}
+	if !covered(hashes, p) || !covered(hashes, Point{-90, 180}) {
+		t.Error("whole world is not covered")
+	}
+	hashes = GeohashCoverCircle(p, 1000, 12)
+	if len(hashes) == 0 || len(hashes) > geohashMaxCover || !covered(hashes, p.Destination(90, 999)) {
+		t.Errorf("bad cover of large circle: %d cells", len(hashes))
+	}
+}
@@ -0,0 +1,350 @@
+// Package geojson реализует чтение и запись геометрий пакета geo в формате GeoJSON (RFC 7946).
+//
+// В памяти geo.Point хранит координаты в порядке (lat, lon), а в GeoJSON позиция всегда
+// записывается в порядке [lon, lat]. Пакет выполняет это преобразование автоматически, поэтому
+// для GeoJSON нужно использовать функции этого пакета, а не json.Marshal для geo.Point.
+//
+// Соответствие типов:
+//
+//	Point              - geo.Point
+//	MultiPoint         - geo.MultiPoint
+//	LineString         - geo.LineString
+//	MultiLineString    - geo.MultiLineString
+//	Polygon            - geo.Polygon
+//	MultiPolygon       - geo.MultiPolygon
+//	GeometryCollection - geo.Collection
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/mdigger/geo"
+)
+
+// Feature описывает объект GeoJSON: геометрию со свойствами.
+type Feature struct {
+	ID         interface{}            // идентификатор: строка или число (может отсутствовать)
+	Geometry   geo.Geometry           // геометрия (может отсутствовать)
+	Properties map[string]interface{} // свойства объекта
+}
+
+// FeatureCollection описывает набор объектов GeoJSON.
+type FeatureCollection struct {
+	Features []*Feature
+}
+
+// position описывает позицию GeoJSON: [lon, lat].
+type position [2]float64
+
+// jsonGeometry описывает геометрию GeoJSON при разборе.
+type jsonGeometry struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []json.RawMessage `json:"geometries"`
+}
+
+// jsonFeature описывает объект GeoJSON.
+type jsonFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   json.RawMessage        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// jsonFeatureCollection описывает набор объектов GeoJSON.
+type jsonFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []json.RawMessage `json:"features"`
+}
+
+// MarshalGeometry возвращает представление геометрии в формате GeoJSON. Внешние контуры
+// многоугольников записываются против часовой стрелки, а "дыры" - по часовой, как того требует
+// RFC 7946. Контуры всегда замыкаются повторением первой точки. Пустая точка (geo.NaNPoint)
+// записывается как null, а для остальных геометрий с пустыми точками возвращается ошибка:
+// GeoJSON не допускает NaN в координатах.
+func MarshalGeometry(g geo.Geometry) ([]byte, error) {
+	if g == nil {
+		return []byte("null"), nil
+	}
+	if c, ok := g.(geo.Collection); ok {
+		geometries := make([]json.RawMessage, len(c))
+		for i, g := range c {
+			data, err := MarshalGeometry(g)
+			if err != nil {
+				return nil, err
+			}
+			geometries[i] = data
+		}
+		return json.Marshal(struct {
+			Type       string            `json:"type"`
+			Geometries []json.RawMessage `json:"geometries"`
+		}{"GeometryCollection", geometries})
+	}
+	var (
+		typ         string
+		coordinates interface{}
+	)
+	switch g := g.(type) {
+	case geo.Point:
+		if math.IsNaN(g.Lat()) || math.IsNaN(g.Lon()) {
+			return []byte("null"), nil
+		}
+		typ, coordinates = "Point", toPosition(g)
+	case geo.MultiPoint:
+		typ, coordinates = "MultiPoint", toPositions(g)
+	case geo.LineString:
+		typ, coordinates = "LineString", toPositions(g)
+	case geo.MultiLineString:
+		lines := make([][]position, len(g))
+		for i, ls := range g {
+			lines[i] = toPositions(ls)
+		}
+		typ, coordinates = "MultiLineString", lines
+	case geo.Polygon:
+		typ, coordinates = "Polygon", toPolygon(g)
+	case geo.MultiPolygon:
+		polygons := make([][][]position, len(g))
+		for i, pg := range g {
+			polygons[i] = toPolygon(pg)
+		}
+		typ, coordinates = "MultiPolygon", polygons
+	default:
+		return nil, fmt.Errorf("geojson: unsupported geometry type %T", g)
+	}
+	data, err := json.Marshal(struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{typ, coordinates})
+	var unsupported *json.UnsupportedValueError
+	if errors.As(err, &unsupported) {
+		return nil, fmt.Errorf("geojson: %s contains empty point or infinite coordinates", typ)
+	}
+	return data, err
+}
+
+// toPosition возвращает позицию GeoJSON для точки.
+func toPosition(p geo.Point) position {
+	return position{p.Lon(), p.Lat()}
+}
+
+// toPositions возвращает список позиций GeoJSON для точек.
+func toPositions(points []geo.Point) []position {
+	positions := make([]position, len(points))
+	for i, p := range points {
+		positions[i] = toPosition(p)
+	}
+	return positions
+}
+
+// toPolygon возвращает контуры многоугольника в виде позиций GeoJSON с правильным
+// направлением обхода.
+func toPolygon(pg geo.Polygon) [][]position {
+	rings := make([][]position, len(pg))
+	for i, r := range pg {
+		ring := toPositions(r)
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0]) // замыкаем контур
+		}
+		// внешний контур - против часовой стрелки, "дыры" - по часовой
+		if r.Clockwise() == (i == 0) {
+			for l, r := 0, len(ring)-1; l < r; l, r = l+1, r-1 {
+				ring[l], ring[r] = ring[r], ring[l]
+			}
+		}
+		rings[i] = ring
+	}
+	return rings
+}
+
+// UnmarshalGeometry разбирает геометрию в формате GeoJSON. Значение null возвращается как nil.
+func UnmarshalGeometry(data []byte) (geo.Geometry, error) {
+	if string(data) == "null" {
+		return nil, nil
+	}
+	var jg jsonGeometry
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return nil, err
+	}
+	if jg.Type == "GeometryCollection" {
+		c := make(geo.Collection, 0, len(jg.Geometries))
+		for _, data := range jg.Geometries {
+			g, err := UnmarshalGeometry(data)
+			if err != nil {
+				return nil, err
+			}
+			if g == nil {
+				return nil, errors.New("geojson: null geometry in collection")
+			}
+			c = append(c, g)
+		}
+		return c, nil
+	}
+	if jg.Coordinates == nil {
+		return nil, fmt.Errorf("geojson: %q without coordinates", jg.Type)
+	}
+	switch jg.Type {
+	case "Point":
+		var pos []float64
+		if err := json.Unmarshal(jg.Coordinates, &pos); err != nil {
+			return nil, err
+		}
+		return fromPosition(pos)
+	case "MultiPoint":
+		var positions [][]float64
+		if err := json.Unmarshal(jg.Coordinates, &positions); err != nil {
+			return nil, err
+		}
+		points, err := fromPositions(positions)
+		return geo.MultiPoint(points), err
+	case "LineString":
+		var positions [][]float64
+		if err := json.Unmarshal(jg.Coordinates, &positions); err != nil {
+			return nil, err
+		}
+		points, err := fromPositions(positions)
+		return geo.LineString(points), err
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(jg.Coordinates, &lines); err != nil {
+			return nil, err
+		}
+		ml := make(geo.MultiLineString, len(lines))
+		for i, line := range lines {
+			points, err := fromPositions(line)
+			if err != nil {
+				return nil, err
+			}
+			ml[i] = points
+		}
+		return ml, nil
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(jg.Coordinates, &rings); err != nil {
+			return nil, err
+		}
+		return fromPolygon(rings)
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(jg.Coordinates, &polygons); err != nil {
+			return nil, err
+		}
+		mp := make(geo.MultiPolygon, len(polygons))
+		for i, rings := range polygons {
+			pg, err := fromPolygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = pg
+		}
+		return mp, nil
+	default:
+		return nil, fmt.Errorf("geojson: unsupported geometry type %q", jg.Type)
+	}
+}
+
+// fromPosition возвращает точку по позиции GeoJSON [lon, lat] или [lon, lat, alt].
+func fromPosition(pos []float64) (geo.Point, error) {
+	if len(pos) < 2 || len(pos) > 3 {
+		return geo.NaNPoint, fmt.Errorf("geojson: bad position %v", pos)
+	}
+	return geo.MakePoint(pos[1], pos[0])
+}
+
+// fromPositions возвращает список точек по позициям GeoJSON.
+func fromPositions(positions [][]float64) ([]geo.Point, error) {
+	points := make([]geo.Point, len(positions))
+	for i, pos := range positions {
+		p, err := fromPosition(pos)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = p
+	}
+	return points, nil
+}
+
+// fromPolygon возвращает многоугольник по контурам GeoJSON.
+func fromPolygon(rings [][][]float64) (geo.Polygon, error) {
+	pg := make(geo.Polygon, len(rings))
+	for i, ring := range rings {
+		points, err := fromPositions(ring)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) < 4 || points[0] != points[len(points)-1] {
+			return nil, errors.New("geojson: polygon ring is not closed")
+		}
+		pg[i] = points
+	}
+	return pg, nil
+}
+
+// MarshalJSON возвращает представление объекта в формате GeoJSON.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	geometry, err := MarshalGeometry(f.Geometry)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonFeature{
+		Type:       "Feature",
+		ID:         f.ID,
+		Geometry:   geometry,
+		Properties: f.Properties,
+	})
+}
+
+// UnmarshalJSON разбирает объект в формате GeoJSON.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var jf jsonFeature
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return err
+	}
+	if jf.Type != "Feature" {
+		return fmt.Errorf("geojson: bad feature type %q", jf.Type)
+	}
+	var geometry geo.Geometry
+	if jf.Geometry != nil {
+		var err error
+		if geometry, err = UnmarshalGeometry(jf.Geometry); err != nil {
+			return err
+		}
+	}
+	*f = Feature{ID: jf.ID, Geometry: geometry, Properties: jf.Properties}
+	return nil
+}
+
+// MarshalJSON возвращает представление набора объектов в формате GeoJSON.
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	features := make([]json.RawMessage, len(fc.Features))
+	for i, f := range fc.Features {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		features[i] = data
+	}
+	return json.Marshal(jsonFeatureCollection{Type: "FeatureCollection", Features: features})
+}
+
+// UnmarshalJSON разбирает набор объектов в формате GeoJSON.
+func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
+	var jfc jsonFeatureCollection
+	if err := json.Unmarshal(data, &jfc); err != nil {
+		return err
+	}
+	if jfc.Type != "FeatureCollection" {
+		return fmt.Errorf("geojson: bad feature collection type %q", jfc.Type)
+	}
+	features := make([]*Feature, len(jfc.Features))
+	for i, data := range jfc.Features {
+		features[i] = new(Feature)
+		if err := json.Unmarshal(data, features[i]); err != nil {
+			return err
+		}
+	}
+	fc.Features = features
+	return nil
+}
@@ -0,0 +1,118 @@
+package geojson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdigger/geo"
+)
+
+func TestGeometryRoundTrip(t *testing.T) {
+	square := geo.Ring{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}} // против часовой стрелки
+	for _, g := range []geo.Geometry{
+		geo.NewPoint(55.7558, 37.6173),
+		geo.MultiPoint{{55, 37}, {56, 38}},
+		geo.LineString{{55, 37}, {56, 38}, {57, 39}},
+		geo.MultiLineString{{{55, 37}, {56, 38}}, {{10, 20}, {11, 21}}},
+		geo.Polygon{square},
+		geo.MultiPolygon{{square}, {square, {{0.2, 0.2}, {0.8, 0.2}, {0.8, 0.8}, {0.2, 0.8}, {0.2, 0.2}}}},
+		geo.Collection{geo.NewPoint(1, 2), geo.LineString{{3, 4}, {5, 6}}},
+		geo.Collection{},
+	} {
+		data, err := MarshalGeometry(g)
+		if err != nil {
+			t.Fatalf("%T: %v", g, err)
+		}
+		g2, err := UnmarshalGeometry(data)
+		if err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if !reflect.DeepEqual(g, g2) {
+			t.Errorf("bad round-trip: %v != %v (%s)", g, g2, data)
+		}
+	}
+	// контур замыкается, а направление обхода исправляется
+	data, err := MarshalGeometry(geo.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}`
+	if string(data) != want {
+		t.Errorf("bad polygon: %s", data)
+	}
+}
+
+func TestPositionOrder(t *testing.T) {
+	data, err := MarshalGeometry(geo.NewPoint(55.7558, 37.6173))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"Point","coordinates":[37.6173,55.7558]}` {
+		t.Errorf("bad point: %s", data)
+	}
+	g, err := UnmarshalGeometry([]byte(`{"type":"Point","coordinates":[37.6173,55.7558,150]}`))
+	if err != nil || g != geo.NewPoint(55.7558, 37.6173) {
+		t.Error("bad point:", g, err)
+	}
+	for _, s := range []string{
+		`{"type":"Point","coordinates":[37.6173]}`,
+		`{"type":"Point","coordinates":[10,95]}`,
+		`{"type":"Point"}`,
+		`{"type":"Circle","coordinates":[1,2]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`,
+	} {
+		if g, err := UnmarshalGeometry([]byte(s)); err == nil {
+			t.Errorf("%s: expected error, got %v", s, g)
+		}
+	}
+}
+
+func TestEmptyPoint(t *testing.T) {
+	data, err := MarshalGeometry(geo.NaNPoint)
+	if err != nil || string(data) != "null" {
+		t.Errorf("bad empty point: %s %v", data, err)
+	}
+	_, err = MarshalGeometry(geo.LineString{geo.NewPoint(1, 2), geo.NaNPoint})
+	if err == nil || !strings.Contains(err.Error(), "LineString") {
+		t.Errorf("expected descriptive error, got %v", err)
+	}
+}
+
+func TestFeatureCollection(t *testing.T) {
+	src := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":"depot-1","geometry":{"type":"Point","coordinates":[37.6173,55.7558]},
+		 "properties":{"name":"Москва","capacity":12}},
+		{"type":"Feature","geometry":null,"properties":null}
+	]}`
+	var fc FeatureCollection
+	if err := json.Unmarshal([]byte(src), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 2 {
+		t.Fatalf("bad features: %v", fc.Features)
+	}
+	f := fc.Features[0]
+	if f.ID != "depot-1" || f.Geometry != geo.NewPoint(55.7558, 37.6173) ||
+		f.Properties["name"] != "Москва" || f.Properties["capacity"] != 12.0 {
+		t.Errorf("bad feature: %+v", f)
+	}
+	if fc.Features[1].Geometry != nil || fc.Features[1].Properties != nil {
+		t.Errorf("bad empty feature: %+v", fc.Features[1])
+	}
+	data, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc2 FeatureCollection
+	if err := json.Unmarshal(data, &fc2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fc, fc2) {
+		t.Errorf("bad round-trip: %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"type":"Feature"}`), &fc); err == nil {
+		t.Error("expected error for bad type")
+	}
+}
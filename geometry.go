@@ -0,0 +1,62 @@
+package geo
+
+// Geometry описывает любую геометрию пакета: Point, MultiPoint, LineString, MultiLineString,
+// Polygon, MultiPolygon или Collection.
+type Geometry interface {
+	// BBox возвращает область, ограничивающую геометрию.
+	BBox() BBox
+}
+
+// MultiPoint описывает набор точек.
+type MultiPoint []Point
+
+// MultiLineString описывает набор линий.
+type MultiLineString []LineString
+
+// Collection описывает набор геометрий разного типа.
+type Collection []Geometry
+
+// BBox возвращает область, состоящую из одной точки.
+func (p Point) BBox() BBox {
+	if p.isNaN() {
+		return EmptyBBox
+	}
+	return BBox{p[0], p[1], p[0], p[1]}
+}
+
+// BBox возвращает область, ограничивающую все точки.
+func (mp MultiPoint) BBox() BBox {
+	return NewBBox(mp...)
+}
+
+// BBox возвращает область, ограничивающую линию.
+func (ls LineString) BBox() BBox {
+	return NewBBox(ls...)
+}
+
+// BBox возвращает область, ограничивающую все линии.
+func (ml MultiLineString) BBox() BBox {
+	var points []Point
+	for _, ls := range ml {
+		points = append(points, ls...)
+	}
+	return NewBBox(points...)
+}
+
+// BBox возвращает область, ограничивающую все многоугольники.
+func (mp MultiPolygon) BBox() BBox {
+	var b = EmptyBBox
+	for _, pg := range mp {
+		b = b.Union(pg.BBox())
+	}
+	return b
+}
+
+// BBox возвращает область, ограничивающую все геометрии набора.
+func (c Collection) BBox() BBox {
+	var b = EmptyBBox
+	for _, g := range c {
+		b = b.Union(g.BBox())
+	}
+	return b
+}
@@ -0,0 +1,522 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// HexCell описывает ячейку иерархической гексагональной сетки, построенной по схеме H3:
+// сфера разбивается на 20 граней икосаэдра, каждая грань отображается гномонической проекцией
+// на плоскость, а плоскость покрывается шестиугольниками. Каждое следующее разрешение
+// уменьшает площадь ячейки в 7 раз и поворачивает сетку на 19.1°. Ячейка существует, если ее
+// центр находится на своей грани, и содержит все точки, для которых ее центр ближайший. Внутри
+// грани это почти правильные шестиугольники, а у ребер граней - многоугольники, объединяющие
+// части шестиугольников соседних граней, без узких обрезков.
+//
+// Идентификаторы ячеек не совместимы с H3. Площади ячеек одного разрешения отличаются не
+// более чем в два раза внутри граней (у центров и углов граней) и не более чем в четыре раза
+// с учетом ячеек на ребрах, поэтому при агрегировании данных для точного нормирования следует
+// использовать Area.
+type HexCell uint64
+
+// MaxHexResolution задает максимальное разрешение гексагональной сетки. Длина ребра ячейки
+// нулевого разрешения около 1100 км, а максимального - около 0.5 м.
+const MaxHexResolution = 15
+
+// Параметры гексагональной сетки.
+var (
+	// ребро ячейки нулевого разрешения в плоскости грани, как у H3
+	hexEdge0 = 1107.712591 / erath_radius
+	// поворот сетки нечетных разрешений относительно четных
+	hexRotation = math.Atan(math.Sqrt(3) / 5)
+	// уменьшение ребра на каждом разрешении
+	hexScale = 1 / math.Sqrt(7)
+	// грани икосаэдра
+	hexFaces = newHexFaces()
+	// смещения соседних шестиугольников в осевых координатах
+	hexAxial = [6][2]int{{1, 0}, {0, 1}, {-1, 1}, {-1, 0}, {0, -1}, {1, -1}}
+)
+
+// Расположение полей в идентификаторе ячейки: разрешение, грань и осевые координаты
+// шестиугольника в плоскости грани со смещением, чтобы они были положительными.
+const (
+	hexCoordBits   = 27
+	hexCoordMask   = 1<<hexCoordBits - 1
+	hexCoordOffset = 1 << (hexCoordBits - 1)
+	hexFaceShift   = 2 * hexCoordBits
+	hexResShift    = hexFaceShift + 5
+)
+
+// hexFace описывает грань икосаэдра и ее гномоническую проекцию.
+type hexFace struct {
+	center, u, v vector        // центр грани и оси плоскости проекции (восток и север)
+	corners      [3][2]float64 // вершины грани в плоскости проекции против часовой стрелки
+}
+
+// newHexFaces строит грани икосаэдра, одна из вершин которого не совпадает с полюсом.
+func newHexFaces() (faces [20]hexFace) {
+	phi := (1 + math.Sqrt(5)) / 2
+	var vs []vector
+	for _, a := range []float64{-1, 1} {
+		for _, b := range []float64{-phi, phi} {
+			vs = append(vs, vector{0, a, b}.normalize(), vector{a, b, 0}.normalize(),
+				vector{b, 0, a}.normalize())
+		}
+	}
+	// соседние вершины икосаэдра находятся под углом arccos(1/√5), остальные - дальше
+	adjacent := func(a, b vector) bool { return a.dot(b) > 0.4 }
+	n := 0
+	for i := range vs {
+		for j := i + 1; j < len(vs); j++ {
+			for k := j + 1; k < len(vs); k++ {
+				if !adjacent(vs[i], vs[j]) || !adjacent(vs[j], vs[k]) || !adjacent(vs[i], vs[k]) {
+					continue
+				}
+				f := &faces[n]
+				f.center = vs[i].add(vs[j]).add(vs[k]).normalize()
+				f.u = vector{0, 0, 1}.cross(f.center).normalize()
+				f.v = f.center.cross(f.u)
+				for c, v := range []vector{vs[i], vs[j], vs[k]} {
+					f.corners[c] = f.project(v)
+				}
+				if polygonArea(f.corners[:]) < 0 {
+					f.corners[1], f.corners[2] = f.corners[2], f.corners[1]
+				}
+				n++
+			}
+		}
+	}
+	return faces
+}
+
+// project возвращает координаты точки в плоскости гномонической проекции грани.
+func (f *hexFace) project(p vector) [2]float64 {
+	q := p.scale(1 / p.dot(f.center))
+	return [2]float64{q.dot(f.u), q.dot(f.v)}
+}
+
+// unproject возвращает точку на сфере по координатам в плоскости грани.
+func (f *hexFace) unproject(xy [2]float64) vector {
+	return f.center.add(f.u.scale(xy[0])).add(f.v.scale(xy[1])).normalize()
+}
+
+// edgeDistance возвращает расстояние в плоскости от точки до ближайшей стороны грани:
+// положительное внутри грани и отрицательное снаружи.
+func (f *hexFace) edgeDistance(xy [2]float64) float64 {
+	d := math.Inf(1)
+	for i, a := range f.corners {
+		b := f.corners[(i+1)%3]
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		d = math.Min(d, (dx*(xy[1]-a[1])-dy*(xy[0]-a[0]))/math.Hypot(dx, dy))
+	}
+	return d
+}
+
+// clipPolygon возвращает часть выпуклого многоугольника, для точек которой side неотрицательна
+// (алгоритм Сазерленда-Ходжмана для одной линейной функции).
+func clipPolygon(polygon [][2]float64, side func(p [2]float64) float64) [][2]float64 {
+	var out [][2]float64
+	for j, p := range polygon {
+		q := polygon[(j+1)%len(polygon)]
+		sp, sq := side(p), side(q)
+		if sp >= 0 {
+			out = append(out, p)
+		}
+		if (sp >= 0) != (sq >= 0) {
+			t := sp / (sp - sq)
+			out = append(out, [2]float64{p[0] + t*(q[0]-p[0]), p[1] + t*(q[1]-p[1])})
+		}
+	}
+	return out
+}
+
+// polygonArea возвращает знаковую площадь многоугольника на плоскости: положительную при
+// обходе против часовой стрелки.
+func polygonArea(polygon [][2]float64) float64 {
+	var area float64
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		area += p[0]*q[1] - q[0]*p[1]
+	}
+	return area / 2
+}
+
+// hexFaceOf возвращает номер грани, ближайшей к точке.
+func hexFaceOf(p vector) int {
+	face, best := 0, math.Inf(-1)
+	for i := range hexFaces {
+		if d := hexFaces[i].center.dot(p); d > best {
+			face, best = i, d
+		}
+	}
+	return face
+}
+
+// hexLattice возвращает длину ребра шестиугольника и угол поворота сетки для разрешения.
+func hexLattice(res int) (edge, angle float64) {
+	return hexEdge0 * math.Pow(hexScale, float64(res)), float64(res%2) * hexRotation
+}
+
+// newHexCell возвращает идентификатор ячейки.
+func newHexCell(res, face, i, j int) HexCell {
+	return HexCell(uint64(res)<<hexResShift | uint64(face)<<hexFaceShift |
+		uint64(i+hexCoordOffset)<<hexCoordBits | uint64(j+hexCoordOffset))
+}
+
+// hexCellAt возвращает ячейку указанного разрешения, содержащую точку в плоскости грани.
+func hexCellAt(res, face int, xy [2]float64) HexCell {
+	edge, angle := hexLattice(res)
+	// поворачиваем плоскость так, чтобы ось X совпала с направлением сетки
+	sin, cos := math.Sincos(-angle)
+	s := math.Sqrt(3) * edge // расстояние между центрами соседних шестиугольников
+	x := (xy[0]*cos - xy[1]*sin) / s
+	y := (xy[0]*sin + xy[1]*cos) / s
+	// осевые координаты и округление в кубических координатах
+	fj := y * 2 / math.Sqrt(3)
+	fi := x - fj/2
+	fk := -fi - fj
+	i, j, k := math.Round(fi), math.Round(fj), math.Round(fk)
+	di, dj, dk := math.Abs(i-fi), math.Abs(j-fj), math.Abs(k-fk)
+	if di > dj && di > dk {
+		i = -j - k
+	} else if dj > dk {
+		j = -i - k
+	}
+	return newHexCell(res, face, int(i), int(j))
+}
+
+// hexCandidates возвращает существующие ячейки разрешения res на всех гранях, центры
+// которых находятся не дальше k шагов в осевых координатах от ячейки, содержащей точку в
+// плоскости грани.
+func hexCandidates(res int, v vector, k int) []HexCell {
+	edge, _ := hexLattice(res)
+	var cells []HexCell
+	for face := range hexFaces {
+		f := &hexFaces[face]
+		if f.center.dot(v) <= 0 {
+			continue
+		}
+		// k шагов покрывают круг радиусом не меньше 1.5*(k+1) ребра
+		xy := f.project(v)
+		if f.edgeDistance(xy) < -1.5*float64(k+1)*edge {
+			continue
+		}
+		for _, c := range hexCellAt(res, face, xy).axialRing(k) {
+			if c.IsValid() {
+				cells = append(cells, c)
+			}
+		}
+	}
+	return cells
+}
+
+// hexNearest возвращает ячейку, центр которой ближе всего к точке.
+func hexNearest(cells []HexCell, v vector) HexCell {
+	var nearest HexCell
+	best := math.Inf(-1)
+	for _, c := range cells {
+		if d := c.vector().dot(v); d > best {
+			nearest, best = c, d
+		}
+	}
+	return nearest
+}
+
+// HexCell возвращает ячейку гексагональной сетки указанного разрешения, содержащую точку.
+// Разрешение ограничивается диапазоном от 0 до MaxHexResolution.
+func (p Point) HexCell(res int) HexCell {
+	if res < 0 {
+		res = 0
+	} else if res > MaxHexResolution {
+		res = MaxHexResolution
+	}
+	return hexCellOf(res, toVector(p))
+}
+
+// hexCellOf возвращает ячейку, центр которой ближе всего к точке.
+func hexCellOf(res int, v vector) HexCell {
+	face := hexFaceOf(v)
+	f := &hexFaces[face]
+	xy := f.project(v)
+	edge, _ := hexLattice(res)
+	// вдали от ребер грани ближайший центр - центр шестиугольника, содержащего точку в
+	// плоскости грани, или одного из его соседей (из-за искажений проекции)
+	if f.edgeDistance(xy) >= 4*edge {
+		return hexNearest(hexCellAt(res, face, xy).axialRing(1), v)
+	}
+	return hexNearest(hexCandidates(res, v, 2), v)
+}
+
+// Resolution возвращает разрешение ячейки.
+func (c HexCell) Resolution() int {
+	return int(c >> hexResShift & 0xf)
+}
+
+// face возвращает номер грани икосаэдра, на которой находится ячейка.
+func (c HexCell) face() int {
+	return int(c >> hexFaceShift & 0x1f)
+}
+
+// axial возвращает осевые координаты шестиугольника в плоскости грани.
+func (c HexCell) axial() (i, j int) {
+	return int(c>>hexCoordBits&hexCoordMask) - hexCoordOffset, int(c&hexCoordMask) - hexCoordOffset
+}
+
+// center возвращает центр шестиугольника в плоскости грани.
+func (c HexCell) center() [2]float64 {
+	edge, angle := hexLattice(c.Resolution())
+	i, j := c.axial()
+	s := math.Sqrt(3) * edge
+	x, y := (float64(i)+float64(j)/2)*s, float64(j)*math.Sqrt(3)/2*s
+	sin, cos := math.Sincos(angle)
+	return [2]float64{x*cos - y*sin, x*sin + y*cos}
+}
+
+// vector возвращает центр ячейки на сфере.
+func (c HexCell) vector() vector {
+	return hexFaces[c.face()].unproject(c.center())
+}
+
+// region возвращает границу ячейки в плоскости ее грани против часовой стрелки и соседние
+// ячейки, с которыми у нее есть общая сторона. Граница между двумя ячейками - дуга большого
+// круга, равноудаленная от их центров, а в плоскости гномонической проекции - прямая.
+func (c HexCell) region() (polygon [][2]float64, neighbors []HexCell) {
+	res, f := c.Resolution(), &hexFaces[c.face()]
+	edge, angle := hexLattice(res)
+	center, v := c.center(), c.vector()
+	// ячейки, которые могут быть соседними: внутри грани - соседние шестиугольники, а у ребер
+	// - все ячейки поблизости на этой и соседних гранях
+	var candidates []HexCell
+	if f.edgeDistance(center) >= 6*edge {
+		candidates = c.axialRing(1)
+	} else {
+		candidates = hexCandidates(res, v, 3)
+	}
+	// начинаем с шестиугольника заведомо большего ячейки и обрезаем его плоскостями,
+	// равноудаленными от центров ячейки и кандидата
+	polygon = make([][2]float64, 6)
+	for k := range polygon {
+		sin, cos := math.Sincos(angle + math.Pi/6 + float64(k)*math.Pi/3)
+		polygon[k] = [2]float64{center[0] + 8*edge*cos, center[1] + 8*edge*sin}
+	}
+	sides := make([]func([2]float64) float64, len(candidates))
+	for i, n := range candidates {
+		if n == c {
+			continue
+		}
+		w := v.add(n.vector().scale(-1))
+		w = w.scale(1 / w.norm())
+		a, b, d := f.center.dot(w), f.u.dot(w), f.v.dot(w)
+		sides[i] = func(p [2]float64) float64 { return a + b*p[0] + d*p[1] }
+		polygon = clipPolygon(polygon, sides[i])
+	}
+	// соседи - ячейки, плоскости которых содержат сторону многоугольника
+	eps := edge * 1e-9
+	for i, n := range candidates {
+		if sides[i] == nil {
+			continue
+		}
+		for j, p := range polygon {
+			q := polygon[(j+1)%len(polygon)]
+			if math.Abs(sides[i](p)) < eps && math.Abs(sides[i](q)) < eps &&
+				math.Hypot(q[0]-p[0], q[1]-p[1]) > 1e3*eps {
+				neighbors = append(neighbors, n)
+				break
+			}
+		}
+	}
+	return polygon, neighbors
+}
+
+// IsValid возвращает true, если идентификатор описывает существующую ячейку: ее центр
+// находится на ее грани и рядом нет центра ячейки грани с меньшим номером. Иначе у ребер
+// граней почти совпадающие центры делили бы область между собой на мелкие ячейки.
+func (c HexCell) IsValid() bool {
+	if c>>63 != 0 || c.Resolution() > MaxHexResolution || c.face() >= len(hexFaces) {
+		return false
+	}
+	v := c.vector()
+	if hexFaceOf(v) != c.face() {
+		return false
+	}
+	edge, _ := hexLattice(c.Resolution())
+	if hexFaces[c.face()].edgeDistance(c.center()) >= edge {
+		return true
+	}
+	for face := 0; face < c.face(); face++ {
+		f := &hexFaces[face]
+		if f.center.dot(v) <= 0 {
+			continue
+		}
+		xy := f.project(v)
+		if f.edgeDistance(xy) < -edge {
+			continue
+		}
+		// ближайший центр сетки другой грани, если он находится на своей грани
+		n := hexCellAt(c.Resolution(), face, xy)
+		if nv := n.vector(); nv.angle(v) < 0.8*edge && hexFaceOf(nv) == face {
+			return false
+		}
+	}
+	return true
+}
+
+// Point возвращает центр ячейки.
+func (c HexCell) Point() Point {
+	return c.vector().point()
+}
+
+// Boundary возвращает границу ячейки против часовой стрелки.
+func (c HexCell) Boundary() Ring {
+	f := &hexFaces[c.face()]
+	polygon, _ := c.region()
+	ring := make(Ring, len(polygon))
+	for i, xy := range polygon {
+		ring[i] = f.unproject(xy).point()
+	}
+	return ring
+}
+
+// Area возвращает площадь ячейки в квадратных километрах.
+func (c HexCell) Area() float64 {
+	return c.Boundary().Area()
+}
+
+// Parent возвращает ячейку меньшего разрешения, содержащую центр данной. Если разрешение не
+// меньше разрешения ячейки, то возвращается сама ячейка.
+func (c HexCell) Parent(res int) HexCell {
+	if res >= c.Resolution() {
+		return c
+	}
+	if res < 0 {
+		res = 0
+	}
+	return hexCellOf(res, c.vector())
+}
+
+// Children возвращает ячейки указанного большего разрешения, центры которых находятся
+// внутри данной: по 7 на каждый уровень, кроме ячеек вблизи ребер граней. Для каждой из
+// возвращаемых ячеек Parent(c.Resolution()) возвращает c.
+func (c HexCell) Children(res int) []HexCell {
+	if res <= c.Resolution() || res > MaxHexResolution {
+		return []HexCell{c}
+	}
+	var children []HexCell
+	next := c.Resolution() + 1
+	// центр ячейки совпадает с центром одного из дочерних шестиугольников, а центры
+	// остальных находятся не дальше второго кольца соседей (у ребер граней - дальше)
+	var candidates []HexCell
+	edge, _ := hexLattice(c.Resolution())
+	if hexFaces[c.face()].edgeDistance(c.center()) >= 6*edge {
+		candidates = hexCellAt(next, c.face(), c.center()).axialRing(2)
+	} else {
+		candidates = hexCandidates(next, c.vector(), 6)
+	}
+	for _, child := range candidates {
+		if child.Parent(c.Resolution()) == c {
+			children = append(children, child.Children(res)...)
+		}
+	}
+	return children
+}
+
+// axialRing возвращает ячейки той же грани на расстоянии не больше k шагов в осевых
+// координатах без проверки их существования.
+func (c HexCell) axialRing(k int) []HexCell {
+	i, j := c.axial()
+	var cells []HexCell
+	for di := -k; di <= k; di++ {
+		for dj := -k; dj <= k; dj++ {
+			if dk := -di - dj; dk >= -k && dk <= k {
+				cells = append(cells, newHexCell(c.Resolution(), c.face(), i+di, j+dj))
+			}
+		}
+	}
+	return cells
+}
+
+// Neighbors возвращает ячейки, имеющие общую границу с данной. Обычно их шесть, но у
+// ячеек на ребрах граней их количество может отличаться. Отношение симметрично: если b
+// является соседом a, то и a является соседом b.
+func (c HexCell) Neighbors() []HexCell {
+	_, neighbors := c.region()
+	return neighbors
+}
+
+// KRing возвращает ячейки, находящиеся не дальше k шагов от данной, включая ее саму,
+// в порядке увеличения расстояния.
+func (c HexCell) KRing(k int) []HexCell {
+	cells := []HexCell{c}
+	seen := map[HexCell]bool{c: true}
+	ring := cells
+	for step := 0; step < k; step++ {
+		var next []HexCell
+		for _, cell := range ring {
+			for _, n := range cell.Neighbors() {
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		cells = append(cells, next...)
+		ring = next
+	}
+	return cells
+}
+
+// HexPolyfill возвращает ячейки указанного разрешения, центры которых находятся внутри
+// многоугольника, отсортированные по идентификатору. Количество ячеек растет в 7 раз с каждым
+// разрешением, поэтому для больших многоугольников следует выбирать разрешение осторожно.
+func HexPolyfill(pg Polygon, res int) []HexCell {
+	if len(pg) == 0 || len(pg[0]) == 0 {
+		return nil
+	}
+	if res < 0 {
+		res = 0
+	} else if res > MaxHexResolution {
+		res = MaxHexResolution
+	}
+	// перебираем ячейки внутри многоугольника и в полосе вдоль его границ, начиная с вершины:
+	// вместе они образуют связную область, содержащую все искомые ячейки
+	edge, _ := hexLattice(res)
+	band := 2 * edge * erath_radius
+	start := pg[0][0].HexCell(res)
+	queue := []HexCell{start}
+	seen := map[HexCell]bool{start: true}
+	var cells []HexCell
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+		center := cell.Point()
+		if pg.Contains(center) {
+			cells = append(cells, cell)
+		}
+		for _, n := range cell.Neighbors() {
+			if !seen[n] {
+				seen[n] = true
+				if p := n.Point(); pg.Contains(p) || pg.DistanceToEdge(p) <= band {
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	return cells
+}
+
+// String возвращает шестнадцатеричное представление идентификатора ячейки.
+func (c HexCell) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// ParseHexCell разбирает шестнадцатеричное представление идентификатора ячейки.
+func ParseHexCell(s string) (HexCell, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || !HexCell(v).IsValid() {
+		return 0, fmt.Errorf("bad hex cell: %q", s)
+	}
+	return HexCell(v), nil
+}
@@ -0,0 +1,229 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHexCellContains(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		res := r.Intn(MaxHexResolution + 1)
+		c := p.HexCell(res)
+		if !c.IsValid() || c.Resolution() != res {
+			t.Fatalf("%v: bad cell %v", p, c)
+		}
+		// точка внутри границы ячейки и недалеко от ее центра
+		if b := c.Boundary(); res < 13 && !b.Contains(p) {
+			t.Errorf("%v: cell %v (res %d) does not contain point", p, c, res)
+		}
+		edge, _ := hexLattice(res)
+		if d := c.Point().Distance(p); d > 2*edge*erath_radius {
+			t.Errorf("%v: cell %v center too far: %f km", p, c, d)
+		}
+		if c2, err := ParseHexCell(c.String()); err != nil || c2 != c {
+			t.Errorf("%v: bad parse %v %v", c, c2, err)
+		}
+	}
+	for _, s := range []string{"", "0", "xyz", "ffffffffffffffff"} {
+		if _, err := ParseHexCell(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+// hexInner возвращает true, если ячейка и ее соседи не обрезаны ребрами грани.
+func hexInner(c HexCell) bool {
+	edge, _ := hexLattice(c.Resolution())
+	return hexFaces[c.face()].edgeDistance(c.center()) >= 3*edge
+}
+
+func TestHexCellGlobal(t *testing.T) {
+	// обходим все ячейки нескольких разрешений через соседей: они должны покрывать всю сферу
+	// без пересечений
+	for res := 0; res <= 2; res++ {
+		start := Point{0, 0}.HexCell(res)
+		seen := map[HexCell]bool{start: true}
+		queue := []HexCell{start}
+		var area, minArea, maxArea float64
+		minArea = math.Inf(1)
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			a := c.Area()
+			area += a
+			if hexInner(c) {
+				minArea, maxArea = math.Min(minArea, a), math.Max(maxArea, a)
+			}
+			for _, n := range c.Neighbors() {
+				if !n.IsValid() {
+					t.Fatalf("%v: invalid neighbour %v", c, n)
+				}
+				if !seen[n] {
+					seen[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		sphere := 4 * math.Pi * erath_radius * erath_radius
+		if math.Abs(area-sphere)/sphere > 1e-9 {
+			t.Errorf("res %d: total area %f, want %f (%d cells)", res, area, sphere, len(seen))
+		}
+		if res == 2 && maxArea/minArea > 2 {
+			t.Errorf("res %d: area ratio %f", res, maxArea/minArea)
+		}
+	}
+}
+
+func TestHexCellHierarchy(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 300; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		res := 1 + r.Intn(10)
+		c := p.HexCell(res)
+		parent := c.Parent(res - 1)
+		if parent.Resolution() != res-1 || !parent.IsValid() {
+			t.Fatalf("%v: bad parent %v", c, parent)
+		}
+		children := parent.Children(res)
+		var found bool
+		for _, child := range children {
+			found = found || child == c
+			if child.Parent(res-1) != parent {
+				t.Errorf("%v: child %v has another parent", parent, child)
+			}
+		}
+		if !found {
+			t.Errorf("%v: not found in children of %v: %v", c, parent, children)
+		}
+		if hexInner(parent) {
+			if len(children) != 7 {
+				t.Errorf("%v: %d children", parent, len(children))
+			}
+			if n := len(parent.Children(res + 1)); n != 49 {
+				t.Errorf("%v: %d grandchildren", parent, n)
+			}
+		}
+	}
+	moscow := Point{55.7539, 37.6208}.HexCell(9)
+	if c := moscow.Parent(9); c != moscow {
+		t.Errorf("parent at same resolution: %v", c)
+	}
+	if c := moscow.Children(9); len(c) != 1 || c[0] != moscow {
+		t.Errorf("children at same resolution: %v", c)
+	}
+}
+
+func TestHexCellKRing(t *testing.T) {
+	c := Point{55.7539, 37.6208}.HexCell(8)
+	for k, want := range []int{1, 7, 19, 37} {
+		if n := len(c.KRing(k)); n != want {
+			t.Errorf("k=%d: %d cells, want %d", k, n, want)
+		}
+	}
+	// соседи симметричны, в том числе на ребрах граней
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		c := p.HexCell(r.Intn(6))
+		for _, n := range c.Neighbors() {
+			var found bool
+			for _, n2 := range n.Neighbors() {
+				found = found || n2 == c
+			}
+			if !found {
+				t.Errorf("%v is neighbour of %v, but not vice versa", n, c)
+			}
+		}
+	}
+}
+
+func TestHexPolyfill(t *testing.T) {
+	// многоугольник примерно в пределах МКАД
+	moscow := Polygon{Ring{{55.57, 37.36}, {55.57, 37.85}, {55.91, 37.85}, {55.91, 37.36}}}
+	cells := HexPolyfill(moscow, 7)
+	if len(cells) == 0 {
+		t.Fatal("no cells")
+	}
+	var area float64
+	for i, c := range cells {
+		if !moscow.Contains(c.Point()) {
+			t.Errorf("%v: center outside polygon", c)
+		}
+		if i > 0 && cells[i-1] >= c {
+			t.Error("cells are not sorted")
+		}
+		area += c.Area()
+	}
+	if ratio := area / moscow.Area(); ratio < 0.9 || ratio > 1.1 {
+		t.Errorf("covered area ratio %f", ratio)
+	}
+	// ячейки с центрами внутри многоугольника не пропущены
+	for _, c := range (Point{55.75, 37.6}).HexCell(7).KRing(3) {
+		if !moscow.Contains(c.Point()) {
+			continue
+		}
+		var found bool
+		for _, c2 := range cells {
+			found = found || c2 == c
+		}
+		if !found {
+			t.Errorf("%v not found", c)
+		}
+	}
+	if cells := HexPolyfill(nil, 5); cells != nil {
+		t.Errorf("cells for empty polygon: %v", cells)
+	}
+}
+
+func TestHexPolyfillPolar(t *testing.T) {
+	// контур вокруг полюса: центры ячеек вблизи полюса лежат выше всех вершин
+	pg := Polygon{Ring{{80, 0}, {80, 90}, {80, 180}, {80, -90}}}
+	cells := HexPolyfill(pg, 3)
+	found := make(map[HexCell]bool, len(cells))
+	for _, c := range cells {
+		found[c] = true
+	}
+	var inside int
+	for _, c := range (Point{89.9, 0}).HexCell(3).KRing(3) {
+		if !pg.Contains(c.Point()) {
+			continue
+		}
+		inside++
+		if !found[c] {
+			t.Errorf("%v at %v not found", c, c.Point())
+		}
+	}
+	if inside == 0 || len(cells) < inside {
+		t.Errorf("%d cells, %d inside k-ring", len(cells), inside)
+	}
+}
+
+func TestHexCellFaceEdge(t *testing.T) {
+	// ячейки вдоль ребра грани не вырождаются в узкие обрезки
+	f := &hexFaces[0]
+	a, b := f.corners[0], f.corners[1]
+	for _, res := range []int{1, 3} {
+		minArea, maxArea := math.Inf(1), 0.0
+		seen := make(map[HexCell]bool)
+		for s := 0; s <= 2000; s++ {
+			k := float64(s) / 2000
+			p := f.unproject([2]float64{a[0] + k*(b[0]-a[0]), a[1] + k*(b[1]-a[1])}).point()
+			c := p.HexCell(res)
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			if !c.Boundary().Contains(p) {
+				t.Errorf("%v: cell %v does not contain point", p, c)
+			}
+			area := c.Area()
+			minArea, maxArea = math.Min(minArea, area), math.Max(maxArea, area)
+		}
+		if ratio := minArea / maxArea; ratio < 0.25 {
+			t.Errorf("res %d: area ratio %f (%f / %f)", res, ratio, minArea, maxArea)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ConvexHull возвращает выпуклую оболочку точек на сфере: контур против часовой стрелки из
+// точек набора, ребра которого - дуги большого круга. Точки должны помещаться в полусферу,
+// иначе возвращается nil. Если все точки лежат на одной дуге, то возвращается контур из
+// двух крайних точек, а для одной точки - из нее самой.
+func ConvexHull(points []Point) Ring {
+	if len(points) == 0 {
+		return nil
+	}
+	vs := make([]vector, len(points))
+	for i, p := range points {
+		vs[i] = toVector(p)
+	}
+	c := center(vs)
+	// в гномонической проекции с центром в середине набора дуги большого круга отображаются
+	// в отрезки прямых, поэтому выпуклая оболочка на плоскости совпадает с оболочкой на сфере
+	e := vector{0, 0, 1}.cross(c)
+	if e.norm() < 1e-12 { // центр на полюсе
+		e = vector{0, 1, 0}
+	}
+	e = e.normalize()
+	n := c.cross(e)
+	type projected struct {
+		x, y float64
+		i    int
+	}
+	ps := make([]projected, len(vs))
+	for i, v := range vs {
+		d := v.dot(c)
+		if d <= 1e-9 {
+			return nil // точки не помещаются в полусферу
+		}
+		ps[i] = projected{v.dot(e) / d, v.dot(n) / d, i}
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].x != ps[j].x {
+			return ps[i].x < ps[j].x
+		}
+		return ps[i].y < ps[j].y
+	})
+	cross := func(o, a, b projected) float64 {
+		return (a.x-o.x)*(b.y-o.y) - (a.y-o.y)*(b.x-o.x)
+	}
+	// алгоритм Эндрю: нижняя и верхняя части оболочки
+	hull := make([]projected, 0, 2*len(ps))
+	for _, p := range ps {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	for i, lower := len(ps)-2, len(hull)+1; i >= 0; i-- {
+		p := ps[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	if len(hull) > 1 {
+		hull = hull[:len(hull)-1] // последняя точка совпадает с первой
+	}
+	ring := make(Ring, len(hull))
+	for i, p := range hull {
+		ring[i] = points[p.i]
+	}
+	return ring
+}
+
+// MinEnclosingCircle возвращает центр и радиус в километрах минимального круга на сфере,
+// содержащего все точки. Используется рандомизированный алгоритм Велзла с ожидаемым
+// линейным временем. Точки должны помещаться в полусферу; для пустого набора возвращается
+// NaNPoint.
+func MinEnclosingCircle(points []Point) (Point, float64) {
+	if len(points) == 0 {
+		return NaNPoint, 0
+	}
+	vs := make([]vector, len(points))
+	for i, p := range points {
+		vs[i] = toVector(p)
+	}
+	// порядок перемешивается для гарантии ожидаемого времени, но результат от него не зависит
+	r := rand.New(rand.NewSource(int64(len(vs))))
+	r.Shuffle(len(vs), func(i, j int) { vs[i], vs[j] = vs[j], vs[i] })
+	const eps = 1e-12 // допуск при проверке попадания точки в круг, в радианах
+	inside := func(c vector, radius float64, v vector) bool {
+		return c.angle(v) <= radius+eps
+	}
+	c, radius := vs[0], 0.0
+	for i := 1; i < len(vs); i++ {
+		if inside(c, radius, vs[i]) {
+			continue
+		}
+		// точка i лежит на границе круга для первых i+1 точек
+		c, radius = vs[i], 0
+		for j := 0; j < i; j++ {
+			if inside(c, radius, vs[j]) {
+				continue
+			}
+			// точки i и j лежат на границе
+			c, radius = circle2(vs[i], vs[j])
+			for k := 0; k < j; k++ {
+				if !inside(c, radius, vs[k]) {
+					c, radius = circle3(vs[i], vs[j], vs[k])
+				}
+			}
+		}
+	}
+	return c.point(), radius * erath_radius
+}
+
+// circle2 возвращает центр и радиус в радианах круга с диаметром между двумя точками.
+func circle2(a, b vector) (vector, float64) {
+	c := a.add(b).normalize()
+	return c, c.angle(a)
+}
+
+// circle3 возвращает центр и радиус в радианах круга, проходящего через три точки. Из двух
+// возможных кругов выбирается меньший.
+func circle3(a, b, c vector) (vector, float64) {
+	n := b.add(a.scale(-1)).cross(c.add(a.scale(-1)))
+	if n.norm() == 0 { // две из трех точек совпадают
+		c1, r1 := circle2(a, b)
+		c2, r2 := circle2(a, c)
+		c3, r3 := circle2(b, c)
+		switch math.Max(r1, math.Max(r2, r3)) {
+		case r1:
+			return c1, r1
+		case r2:
+			return c2, r2
+		default:
+			return c3, r3
+		}
+	}
+	n = n.normalize()
+	if n.dot(a) < 0 {
+		n = n.scale(-1)
+	}
+	return n, n.angle(a)
+}
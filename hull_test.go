@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	points := []Point{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {1, 1}, {0.5, 1.5}, {0, 1}}
+	hull := ConvexHull(points)
+	if len(hull) != 4 {
+		t.Fatalf("bad hull: %v", hull)
+	}
+	if hull.Clockwise() {
+		t.Error("hull is clockwise")
+	}
+	for _, p := range points {
+		if !hull.Contains(p) && hull.DistanceToEdge(p) > 1e-6 {
+			t.Errorf("%v is outside hull", p)
+		}
+	}
+	// на сфере оболочка отличается от плоской: дуга большого круга между точками на одной
+	// широте проходит севернее параллели
+	hull = ConvexHull([]Point{{60, 0}, {60, 90}, {61, 45}, {50, 45}})
+	if len(hull) != 3 {
+		t.Errorf("bad spherical hull: %v", hull)
+	}
+	r := rand.New(rand.NewSource(1))
+	points = points[:0]
+	for i := 0; i < 500; i++ {
+		points = append(points, Point{55 + r.NormFloat64(), 37 + r.NormFloat64()})
+	}
+	hull = ConvexHull(points)
+	for _, p := range points {
+		if !hull.Contains(p) && hull.DistanceToEdge(p) > 1e-6 {
+			t.Errorf("%v is outside hull", p)
+		}
+	}
+	if h := ConvexHull([]Point{{0, 0}, {0, 1}, {0, 2}}); len(h) != 2 {
+		t.Errorf("bad collinear hull: %v", h)
+	}
+	if h := ConvexHull([]Point{{10, 10}}); len(h) != 1 {
+		t.Errorf("bad single point hull: %v", h)
+	}
+	if h := ConvexHull([]Point{{0, 0}, {0, 120}, {0, -120}}); h != nil {
+		t.Errorf("expected nil for points around the globe: %v", h)
+	}
+	if h := ConvexHull(nil); h != nil {
+		t.Errorf("bad empty hull: %v", h)
+	}
+}
+
+func TestMinEnclosingCircle(t *testing.T) {
+	// две точки на экваторе: центр посередине
+	c, r := MinEnclosingCircle([]Point{{0, 0}, {0, 2}, {0, 1}, {0.1, 1.5}})
+	if c.Distance(Point{0, 1}) > 1e-9 || math.Abs(r-Point{0, 0}.Distance(Point{0, 1})) > 1e-9 {
+		t.Errorf("bad circle: %v %f", c, r)
+	}
+	// равносторонний треугольник: центр равноудален от вершин
+	a := Point{0, 0}
+	b := a.Destination(60, 100)
+	triangle := []Point{a, b, a.Destination(120, 100)}
+	c, r = MinEnclosingCircle(triangle)
+	for _, p := range triangle {
+		if math.Abs(c.Distance(p)-r) > 1e-6 {
+			t.Errorf("%v: distance %f, radius %f", p, c.Distance(p), r)
+		}
+	}
+	rnd := rand.New(rand.NewSource(2))
+	for n := 1; n < 200; n += 13 {
+		var points []Point
+		for i := 0; i < n; i++ {
+			points = append(points, Point{rnd.Float64()*60 - 30, rnd.Float64()*60 + 150})
+		}
+		c, r := MinEnclosingCircle(points)
+		var max float64
+		for _, p := range points {
+			max = math.Max(max, c.Distance(p))
+		}
+		// все точки внутри, и хотя бы одна на границе
+		if math.Abs(max-r) > 1e-6 {
+			t.Errorf("%d points: radius %f, max distance %f", n, r, max)
+		}
+		// смещение центра в любую сторону только увеличивает радиус
+		for bearing := 0.0; bearing < 360; bearing += 45 {
+			c2 := c.Destination(bearing, 1)
+			var max2 float64
+			for _, p := range points {
+				max2 = math.Max(max2, c2.Distance(p))
+			}
+			if n > 1 && max2 < r-1e-6 {
+				t.Errorf("%d points: circle is not minimal", n)
+			}
+		}
+	}
+	if c, r := MinEnclosingCircle(nil); !c.isNaN() || r != 0 {
+		t.Errorf("bad empty circle: %v %f", c, r)
+	}
+}
@@ -0,0 +1,339 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"sync"
+)
+
+// IndexItem описывает элемент пространственного индекса: точку и связанные с ней данные.
+type IndexItem struct {
+	Point    Point       // координаты
+	Value    interface{} // связанные с точкой данные
+	Distance float64     // расстояние до точки запроса в километрах (заполняется при поиске)
+}
+
+// Index описывает пространственный индекс точек для поиска ближайших соседей и точек в заданном
+// радиусе. Индекс построен как k-d дерево по координатам точек на единичной сфере, поэтому
+// корректно работает у полюсов и меридиана ±180°. Дерево поддерживается сбалансированным
+// частичным перестроением поддеревьев. Индекс безопасен для использования из нескольких
+// goroutine одновременно.
+type Index struct {
+	root    *kdNode
+	deleted int // количество удаленных, но еще не убранных из дерева элементов
+	mu      sync.RWMutex
+}
+
+// kdNode описывает узел k-d дерева.
+type kdNode struct {
+	item        IndexItem
+	v           vector // координаты точки на единичной сфере
+	axis        int    // ось разделения: 0 - x, 1 - y, 2 - z
+	left, right *kdNode
+	size        int  // количество узлов в поддереве, включая удаленные
+	deleted     bool // элемент удален
+}
+
+// kdAlpha задает допустимую несбалансированность поддерева: если одна из ветвей содержит
+// больше этой доли узлов, то поддерево перестраивается.
+const kdAlpha = 0.7
+
+// NewIndex возвращает новый пространственный индекс с указанными элементами.
+func NewIndex(items ...IndexItem) *Index {
+	nodes := make([]*kdNode, len(items))
+	for i, item := range items {
+		nodes[i] = &kdNode{item: item, v: toVector(item.Point)}
+	}
+	return &Index{root: buildKD(nodes)}
+}
+
+// buildKD строит сбалансированное дерево из узлов, разделяя их по оси с наибольшим разбросом.
+func buildKD(nodes []*kdNode) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	var lo, hi = vector{1, 1, 1}, vector{-1, -1, -1}
+	for _, n := range nodes {
+		for i := range n.v {
+			lo[i], hi[i] = math.Min(lo[i], n.v[i]), math.Max(hi[i], n.v[i])
+		}
+	}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if hi[i]-lo[i] > hi[axis]-lo[axis] {
+			axis = i
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].v[axis] < nodes[j].v[axis] })
+	m := len(nodes) / 2
+	node := nodes[m]
+	node.axis = axis
+	node.left = buildKD(nodes[:m])
+	node.right = buildKD(nodes[m+1:])
+	node.size = len(nodes)
+	node.deleted = false
+	return node
+}
+
+// collect добавляет в список все неудаленные узлы поддерева.
+func (n *kdNode) collect(nodes []*kdNode) []*kdNode {
+	if n == nil {
+		return nodes
+	}
+	if !n.deleted {
+		nodes = append(nodes, n)
+	}
+	nodes = n.left.collect(nodes)
+	return n.right.collect(nodes)
+}
+
+// sizeOf возвращает количество узлов в поддереве.
+func (n *kdNode) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// Len возвращает количество элементов в индексе.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.root.sizeOf() - idx.deleted
+}
+
+// Insert добавляет в индекс точку со связанными с ней данными.
+func (idx *Index) Insert(p Point, value interface{}) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	node := &kdNode{item: IndexItem{Point: p, Value: value}, v: toVector(p), size: 1}
+	// спускаемся по дереву, запоминая путь
+	var path []**kdNode
+	link := &idx.root
+	for *link != nil {
+		path = append(path, link)
+		parent := *link
+		parent.size++
+		node.axis = (parent.axis + 1) % 3
+		if node.v[parent.axis] < parent.v[parent.axis] {
+			link = &parent.left
+		} else {
+			link = &parent.right
+		}
+	}
+	*link = node
+	// перестраиваем самое верхнее несбалансированное поддерево на пути
+	for _, link := range path {
+		n := *link
+		if float64(n.left.sizeOf()) > kdAlpha*float64(n.size) ||
+			float64(n.right.sizeOf()) > kdAlpha*float64(n.size) {
+			removed := n.size
+			*link = buildKD(n.collect(nil))
+			idx.adjustSizes(path, link, (*link).sizeOf()-removed)
+			break
+		}
+	}
+}
+
+// adjustSizes корректирует размеры поддеревьев на пути выше указанного узла.
+func (idx *Index) adjustSizes(path []**kdNode, until **kdNode, delta int) {
+	if delta == 0 {
+		return
+	}
+	for _, link := range path {
+		if link == until {
+			break
+		}
+		(*link).size += delta
+	}
+	idx.deleted += delta // удаленные узлы убраны из дерева при перестроении
+}
+
+// Delete удаляет из индекса элемент с указанными координатами и данными. Данные сравниваются
+// оператором ==, поэтому должны быть сравнимого типа. Возвращает false, если элемент не найден.
+func (idx *Index) Delete(p Point, value interface{}) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	node := idx.root.find(toVector(p), p, value)
+	if node == nil {
+		return false
+	}
+	node.deleted = true
+	idx.deleted++
+	// если удаленных элементов больше, чем оставшихся, то перестраиваем дерево целиком
+	if idx.deleted > idx.root.sizeOf()/2 {
+		idx.root = buildKD(idx.root.collect(nil))
+		idx.deleted = 0
+	}
+	return true
+}
+
+// find ищет в поддереве неудаленный узел с указанными координатами и данными.
+func (n *kdNode) find(v vector, p Point, value interface{}) *kdNode {
+	if n == nil {
+		return nil
+	}
+	if !n.deleted && n.item.Point == p && n.item.Value == value {
+		return n
+	}
+	if v[n.axis] <= n.v[n.axis] {
+		if found := n.left.find(v, p, value); found != nil {
+			return found
+		}
+	}
+	if v[n.axis] >= n.v[n.axis] {
+		return n.right.find(v, p, value)
+	}
+	return nil
+}
+
+// chord возвращает квадрат длины хорды единичной сферы между двумя точками.
+func chord(v1, v2 vector) float64 {
+	d := vector{v1[0] - v2[0], v1[1] - v2[1], v1[2] - v2[2]}
+	return d.dot(d)
+}
+
+// chordToDistance переводит квадрат длины хорды в расстояние по сфере в километрах.
+func chordToDistance(c float64) float64 {
+	return 2 * math.Asin(math.Min(1, math.Sqrt(c)/2)) * erath_radius
+}
+
+// result возвращает элемент узла с заполненным расстоянием до точки запроса.
+func (n *kdNode) result(c float64) IndexItem {
+	item := n.item
+	item.Distance = chordToDistance(c)
+	return item
+}
+
+// Nearest возвращает k ближайших к точке элементов индекса, отсортированных по возрастанию
+// расстояния.
+func (idx *Index) Nearest(p Point, k int) []IndexItem {
+	if k <= 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	h := make(kdHeap, 0, k)
+	idx.root.nearest(toVector(p), k, &h)
+	items := make([]IndexItem, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		candidate := heap.Pop(&h).(kdCandidate)
+		items[i] = candidate.node.result(candidate.chord)
+	}
+	return items
+}
+
+// nearest собирает в куче k ближайших к точке узлов поддерева.
+func (n *kdNode) nearest(v vector, k int, h *kdHeap) {
+	if n == nil {
+		return
+	}
+	if !n.deleted {
+		c := chord(v, n.v)
+		if h.Len() < k {
+			heap.Push(h, kdCandidate{n, c})
+		} else if c < (*h)[0].chord {
+			(*h)[0] = kdCandidate{n, c}
+			heap.Fix(h, 0)
+		}
+	}
+	diff := v[n.axis] - n.v[n.axis]
+	near, far := n.left, n.right
+	if diff >= 0 {
+		near, far = far, near
+	}
+	near.nearest(v, k, h)
+	if h.Len() < k || diff*diff < (*h)[0].chord {
+		far.nearest(v, k, h)
+	}
+}
+
+// Within возвращает все элементы индекса, находящиеся не дальше radius километров от точки,
+// отсортированные по возрастанию расстояния.
+func (idx *Index) Within(p Point, radius float64) []IndexItem {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	items := idx.within(toVector(p), radius)
+	sort.Slice(items, func(i, j int) bool { return items[i].Distance < items[j].Distance })
+	return items
+}
+
+// within возвращает все элементы, находящиеся не дальше radius километров от точки.
+func (idx *Index) within(v vector, radius float64) []IndexItem {
+	if radius < 0 {
+		return nil
+	}
+	// квадрат хорды, соответствующей радиусу
+	c := 2 * math.Sin(math.Min(radius/erath_radius, math.Pi)/2)
+	var items []IndexItem
+	idx.root.within(v, c*c, &items)
+	return items
+}
+
+// within собирает узлы поддерева, квадрат хорды до которых не превышает maxChord.
+func (n *kdNode) within(v vector, maxChord float64, items *[]IndexItem) {
+	if n == nil {
+		return
+	}
+	if !n.deleted {
+		if c := chord(v, n.v); c <= maxChord {
+			*items = append(*items, n.result(c))
+		}
+	}
+	diff := v[n.axis] - n.v[n.axis]
+	if diff <= 0 || diff*diff <= maxChord {
+		n.left.within(v, maxChord, items)
+	}
+	if diff >= 0 || diff*diff <= maxChord {
+		n.right.within(v, maxChord, items)
+	}
+}
+
+// WithinBBox возвращает все элементы индекса, попадающие в область. Поле Distance содержит
+// расстояние до центра области.
+func (idx *Index) WithinBBox(b BBox) []IndexItem {
+	if b.IsEmpty() {
+		return nil
+	}
+	// ищем в описанном вокруг области круге. Если область не шире полушария, то самые дальние от
+	// центра точки - ее углы. Для более широких областей дальше всего могут оказаться середины
+	// боковых границ, поэтому просматриваем всю сферу.
+	center := b.Center()
+	radius := math.Pi * erath_radius
+	if b.lonSpan() <= 180 {
+		radius = 0
+		for _, corner := range []Point{{b.South, b.West}, {b.South, b.East}, {b.North, b.West}, {b.North, b.East}} {
+			radius = math.Max(radius, center.Distance(corner))
+		}
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var items []IndexItem
+	for _, item := range idx.within(toVector(center), radius*(1+1e-9)) {
+		if b.Contains(item.Point) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// kdCandidate описывает кандидата при поиске ближайших соседей.
+type kdCandidate struct {
+	node  *kdNode
+	chord float64
+}
+
+// kdHeap описывает кучу кандидатов, на вершине которой находится самый дальний.
+type kdHeap []kdCandidate
+
+func (h kdHeap) Len() int            { return len(h) }
+func (h kdHeap) Less(i, j int) bool  { return h[i].chord > h[j].chord }
+func (h kdHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *kdHeap) Push(x interface{}) { *h = append(*h, x.(kdCandidate)) }
+func (h *kdHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// randomPoints возвращает n случайных точек в указанной области.
+func randomPoints(r *rand.Rand, n int, b BBox) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{
+			b.South + r.Float64()*(b.North-b.South),
+			normalizeLon(b.West + r.Float64()*b.lonSpan()),
+		}
+	}
+	return points
+}
+
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	idx := NewIndex()
+	points := randomPoints(r, 2000, BBox{South: 60, West: 170, North: 72, East: -170})
+	// добавляем точки в отсортированном порядке, чтобы проверить балансировку
+	sort.Slice(points, func(i, j int) bool { return points[i].Lon() < points[j].Lon() })
+	for i, p := range points {
+		idx.Insert(p, i)
+	}
+	if idx.Len() != len(points) {
+		t.Fatalf("bad length: %d", idx.Len())
+	}
+	for i := 0; i < len(points); i += 2 {
+		if !idx.Delete(points[i], i) {
+			t.Fatalf("point %d not deleted", i)
+		}
+	}
+	if idx.Delete(points[0], 0) || idx.Delete(points[1], 0) {
+		t.Error("deleted missing item")
+	}
+	if idx.Len() != len(points)/2 {
+		t.Fatalf("bad length after delete: %d", idx.Len())
+	}
+	for _, q := range randomPoints(r, 20, BBox{South: 58, West: 165, North: 74, East: -165}) {
+		// полный перебор для сравнения
+		var want []float64
+		for i := 1; i < len(points); i += 2 {
+			want = append(want, q.Distance(points[i]))
+		}
+		sort.Float64s(want)
+		nearest := idx.Nearest(q, 5)
+		if len(nearest) != 5 {
+			t.Fatalf("bad nearest count: %d", len(nearest))
+		}
+		for i, item := range nearest {
+			if math.Abs(item.Distance-want[i]) > 1e-6 || item.Value.(int)%2 != 1 {
+				t.Errorf("bad nearest %d: %v, want %f", i, item, want[i])
+			}
+		}
+		within := idx.Within(q, 100)
+		count := sort.SearchFloat64s(want, 100+1e-9)
+		if len(within) != count {
+			t.Errorf("bad within count: %d, want %d", len(within), count)
+		}
+	}
+	b := BBox{South: 64, West: 178, North: 66, East: -178}
+	var count int
+	for i := 1; i < len(points); i += 2 {
+		if b.Contains(points[i]) {
+			count++
+		}
+	}
+	items := idx.WithinBBox(b)
+	if len(items) != count || count == 0 {
+		t.Errorf("bad bbox count: %d, want %d", len(items), count)
+	}
+}
+
+func TestIndexConcurrent(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := randomPoints(r, 1000, BBox{South: 40, West: 20, North: 70, East: 60})
+	idx := NewIndex()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(points); i += 4 {
+				idx.Insert(points[i], i)
+				idx.Nearest(points[i], 3)
+				idx.Within(points[i], 50)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if idx.Len() != len(points) {
+		t.Errorf("bad length: %d", idx.Len())
+	}
+	if items := idx.Nearest(points[10], 1); len(items) != 1 || items[0].Distance != 0 {
+		t.Errorf("bad nearest: %v", items)
+	}
+}
+
+func TestIndexWithinWideBBox(t *testing.T) {
+	idx := NewIndex(
+		IndexItem{Point: Point{0, 179}, Value: 1},
+		IndexItem{Point: Point{0, -179}, Value: 2},
+		IndexItem{Point: Point{0, 180}, Value: 3},
+		IndexItem{Point: Point{5, -180}, Value: 4},
+		IndexItem{Point: Point{0, 0}, Value: 5},
+		IndexItem{Point: Point{70, 90}, Value: 6},
+	)
+	for _, test := range []struct {
+		b    BBox
+		want int
+	}{
+		{BBox{South: -60, West: -179, North: 60, East: 179}, 3},
+		{BBox{South: -10, West: -180, North: 10, East: 180}, 5},
+		{BBox{South: -10, West: 170, North: 10, East: 160}, 5},
+		{BBox{South: -10, West: 179, North: 10, East: -179}, 4},
+	} {
+		if items := idx.WithinBBox(test.b); len(items) != test.want {
+			t.Errorf("%v: bad count: %d, want %d", test.b, len(items), test.want)
+		}
+	}
+}
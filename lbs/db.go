@@ -15,42 +15,193 @@ import (
 )
 
 // DB описывает базу данных по сотовым сетям. В качестве ключа выступает строка в формате:
-// Request.MCC-Request.MNC. Вторым ключем идет: Cell.Area-Cell.ID
-type DB map[string]map[string][]geo.Point
+// Cell.Radio:Request.MCC:Request.MNC, например "LTE:250:1". Вторым ключем идет:
+// Cell.Area:Cell.ID. Для каждой станции хранятся записи Tower с координатами в компактном виде
+// geo.FixedPoint и атрибутами, по которым можно оценить их качество.
+type DB map[string]map[string][]Tower
 
-// Find делает выборку из базы данных всех подходящих координат станций и возвращает их.
-func (db DB) Find(req *Request) geo.Point {
-	if req == nil || db == nil {
-		return geo.NaNPoint
-	}
-	id := fmt.Sprintf("%d:%d", req.MCC, req.MNC) // формируем первичный ключ запроса
-	sdb, ok := db[id]                            // получаем вложенный раздел базы
-	if !ok {
-		return geo.NaNPoint // вложенный раздел не найден
+// dbKey возвращает первичный ключ базы данных для типа сети и оператора.
+func dbKey(radio Radio, mcc uint16, mnc uint32) string {
+	return fmt.Sprintf("%s:%d:%d", radio, mcc, mnc)
+}
+
+// cellKey возвращает вторичный ключ базы данных для станции.
+func cellKey(area uint32, id uint64) string {
+	return fmt.Sprintf("%d:%d", area, id)
+}
+
+// dbVersion задает версию формата файла базы данных. Она записывается перед данными и
+// увеличивается при любом изменении структуры DB или Tower.
+const dbVersion = 2
+
+// defaultRange задает радиус действия станции в метрах, если он не известен.
+const defaultRange = 2000
+
+// Find определяет координаты устройства по базовым станциям из запроса с помощью
+// DefaultEstimator. Подробнее в описании FindWith.
+func (db DB) Find(req *Request) *Result {
+	return db.FindWith(req, DefaultEstimator)
+}
+
+// FindWith определяет координаты устройства по базовым станциям из запроса с помощью
+// указанного алгоритма. Станции в запросе могут относиться к разным типам сетей одного
+// оператора. Если ни одна из станций не найдена, то используется центр всех известных станций
+// из тех же зон (LAC/TAC): для этого перебираются все станции оператора, поэтому такой поиск
+// медленнее. Станции без идентификатора, для которых указан PCI/PSC, ищутся по полю Tower.Unit
+// среди станций той же зоны (или зоны обслуживающей станции, если зона не указана); из
+// нескольких станций с одинаковым PCI выбирается ближайшая к обслуживающей.
+func (db DB) FindWith(req *Request, estimator Estimator) *Result {
+	result := &Result{Point: geo.NaNPoint}
+	if req == nil || db == nil || estimator == nil {
+		result.Err = ErrEmptyRequest
+		return result
 	}
-	var sm, slat, slon float64
+	var (
+		obs       []Observation // найденные станции
+		unmatched []*Cell       // станции, не найденные в базе
+		unknown   int           // станции без идентификатора
+		byUnit    []*Cell       // станции, для которых известен только PCI/PSC
+		network   bool          // в базе есть данные об операторе
+	)
+	sdbs := make(map[Radio]map[string][]Tower) // найденные разделы базы для типов сетей
 	// перебираем все данные о сетях
 	for _, cell := range req.Cells {
-		sid := fmt.Sprintf("%d:%d", cell.Area, cell.ID)
-		points, ok := sdb[sid]
-		if !ok {
-			continue // игнорируем
+		if cell == nil || (cell.ID == 0 && cell.PCI == 0) {
+			unknown++ // станцию без идентификатора нельзя найти в базе
+			continue
 		}
-		// перебираем все доступные данные для станции
-		for _, point := range points {
-			m := math.Pow(10, (float64(cell.DBM)/20)) * 1000
-			sm += m
-			slat += point.Lat() * m
-			slon += point.Lon() * m
+		sdb, ok := db[dbKey(cell.Radio, req.MCC, req.MNC)] // получаем вложенный раздел базы
+		if ok {
+			network = true
+			sdbs[cell.Radio] = sdb
 		}
+		if cell.ID == 0 {
+			byUnit = append(byUnit, cell) // ищем после обслуживающей станции
+			continue
+		}
+		towers, ok := sdb[cellKey(cell.Area, cell.ID)]
+		if !ok || len(towers) == 0 {
+			unmatched = append(unmatched, cell)
+			continue
+		}
+		obs = append(obs, Observation{Cell: cell, Towers: towers})
 	}
-	if sm == 0 {
-		return geo.NaNPoint
+	for _, cell := range byUnit {
+		towers := findUnit(sdbs[cell.Radio], cell, obs)
+		if towers == nil {
+			unknown++
+			continue
+		}
+		obs = append(obs, Observation{Cell: cell, Towers: towers})
 	}
-	return geo.NewPoint(slat/sm, slon/sm)
+	result.Matched, result.Unmatched = len(obs), len(unmatched)+unknown
+	switch {
+	case len(obs)+len(unmatched) == 0:
+		result.Err = ErrNoCells
+		return result
+	case !network:
+		result.Err = ErrUnknownNetwork
+		return result
+	case result.Matched > 0:
+		var used []Observation
+		result.Point, used = estimator.Estimate(obs)
+		result.Rejected = len(obs) - len(used)
+		result.Method = estimator.Method()
+		if len(obs) == 1 {
+			result.Method = MethodSingleCell
+		}
+		// устройство находится в зоне действия каждой из использованных станций
+		for i, o := range used {
+			radius := o.Range() + result.Point.Distance(o.Position())*1000
+			if i == 0 || radius < result.Accuracy {
+				result.Accuracy = radius
+			}
+		}
+		return result
+	}
+	// ни одна станция не найдена: собираем все станции из тех же зон
+	var towers []Tower
+	areas := make(map[string]bool)
+	for _, cell := range unmatched {
+		sdb := sdbs[cell.Radio]
+		prefix := fmt.Sprintf("%d:", cell.Area)
+		area := cell.Radio.String() + ":" + prefix
+		if sdb == nil || areas[area] {
+			continue
+		}
+		areas[area] = true
+		for sid, areaTowers := range sdb {
+			if strings.HasPrefix(sid, prefix) {
+				towers = append(towers, areaTowers...)
+			}
+		}
+	}
+	if len(towers) == 0 {
+		result.Err = ErrCellsNotFound
+		return result
+	}
+	points := make([]geo.Point, len(towers))
+	weights := make([]float64, len(towers))
+	for i, tower := range towers {
+		points[i] = tower.Point.Point()
+		weights[i] = 1
+	}
+	result.Point = centroid(points, weights)
+	result.Method = MethodAreaFallback
+	// устройство находится в зоне действия хотя бы одной из станций зоны
+	for _, tower := range towers {
+		radius := Observation{Towers: []Tower{tower}}.Range() +
+			result.Point.Distance(tower.Point.Point())*1000
+		result.Accuracy = math.Max(result.Accuracy, radius)
+	}
+	return result
+}
+
+// findUnit ищет станцию, для которой известен только PCI/PSC. Обслуживающей считается первая
+// найденная станция того же типа сети. Если ее нет, а станций с таким PCI в зоне несколько, то
+// станция считается не найденной. Для поиска перебираются все станции оператора.
+func findUnit(sdb map[string][]Tower, cell *Cell, obs []Observation) []Tower {
+	var serving *Observation
+	for i := range obs {
+		if obs[i].Cell.Radio == cell.Radio {
+			serving = &obs[i]
+			break
+		}
+	}
+	area := cell.Area
+	if area == 0 && serving != nil {
+		area = serving.Cell.Area
+	}
+	if sdb == nil || area == 0 {
+		return nil
+	}
+	prefix := fmt.Sprintf("%d:", area)
+	var (
+		found    []Tower
+		count    int
+		distance float64
+	)
+	for sid, towers := range sdb {
+		if !strings.HasPrefix(sid, prefix) || len(towers) == 0 || towers[0].Unit != cell.PCI {
+			continue
+		}
+		count++
+		if serving == nil {
+			found = towers
+			continue
+		}
+		d := serving.Position().Distance(Observation{Towers: towers}.Position())
+		if found == nil || d < distance {
+			found, distance = towers, d
+		}
+	}
+	if count > 1 && serving == nil {
+		return nil
+	}
+	return found
 }
 
-// Save сохраняет базу данных в файл.
+// Save сохраняет базу данных в файл вместе с версией формата.
 func (db DB) Save(filename string) error {
 	log.Printf("Save DB %q", filename)
 	file, err := os.Create(filename)
@@ -58,7 +209,11 @@ func (db DB) Save(filename string) error {
 		return err
 	}
 	defer file.Close()
-	return gob.NewEncoder(file).Encode(db)
+	enc := gob.NewEncoder(file)
+	if err := enc.Encode(dbVersion); err != nil {
+		return err
+	}
+	return enc.Encode(db)
 }
 
 // Len возвращает количество записей в базе данных.
@@ -72,7 +227,9 @@ func (db DB) Len() int {
 	return length
 }
 
-// LoadDB загружает базу данных из файла
+// LoadDB загружает базу данных из файла. Файлы, сохраненные в другой версии формата (в том
+// числе более ранними версиями библиотеки без номера версии), не загружаются: базу данных
+// нужно заново импортировать из CSV.
 func LoadDB(filename string) (DB, error) {
 	log.Printf("Load DB %q", filename)
 	file, err := os.Open(filename)
@@ -80,26 +237,53 @@ func LoadDB(filename string) (DB, error) {
 		return nil, err
 	}
 	defer file.Close()
+	dec := gob.NewDecoder(file)
+	var version int
+	if err := dec.Decode(&version); err != nil {
+		return nil, fmt.Errorf("unsupported DB format in %q (old version?), re-import it from CSV: %v",
+			filename, err)
+	}
+	if version != dbVersion {
+		return nil, fmt.Errorf("unsupported DB format version %d in %q (want %d), re-import it from CSV",
+			version, filename, dbVersion)
+	}
 	var db = make(DB)
-	if err := gob.NewDecoder(file).Decode(&db); err != nil {
+	if err := dec.Decode(&db); err != nil {
 		return nil, err
 	}
 	return db, nil
 }
 
-// ImportCSV импортирует данные из формата CSV.
-// В данный момент захардкодено игнорирование первой строки (как заголовка) и всех данных,
-// которые не относятся к сети GSM.
+// ImportCSV импортирует данные из формата CSV (OpenCelliD или Mozilla Location Service) с
+// параметрами DefaultImportOptions. Подробнее в описании ImportCSVWithOptions.
 func ImportCSV(filename string) (DB, error) {
+	return ImportCSVWithOptions(filename, nil)
+}
+
+// ImportCSVWithOptions импортирует данные из формата CSV (OpenCelliD или Mozilla Location
+// Service). Колонки определяются по названиям в заголовке, поэтому их порядок не важен; для
+// файла без заголовка (ImportOptions.NoHeader) используется порядок колонок OpenCelliD.
+// Параметры фильтрации задаются opts; если они не указаны, то используются
+// DefaultImportOptions. Импортируются станции сетей GSM, UMTS, LTE, NR и CDMA; записи с
+// неизвестным типом сети пропускаются.
+func ImportCSVWithOptions(filename string, opts *ImportOptions) (DB, error) {
 	log.Printf("Import DB from CSV %q", filename)
+	if opts == nil {
+		opts = &DefaultImportOptions
+	}
+	filter, err := opts.filter()
+	if err != nil {
+		return nil, err
+	}
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	db := make(DB)     // создаем новую базу данных
-	var counter uint32 // счетчик
+	db := make(DB)      // создаем новую базу данных
+	var counter uint32  // счетчик
+	var cols csvColumns // номера колонок
 	r := csv.NewReader(file)
 	for {
 		record, err := r.Read()
@@ -112,37 +296,126 @@ func ImportCSV(filename string) (DB, error) {
 		counter++
 		if counter == 1 {
 			r.FieldsPerRecord = len(record) // устанавливаем количество полей
-			continue                        // пропускаем первую строку с заголовком в CSV-файле
+			if opts.NoHeader {
+				cols = defaultColumns(len(record))
+				if cols[colLat] < 0 {
+					return nil, fmt.Errorf("not enough CSV fields: %d", len(record))
+				}
+			} else if cols, err = headerColumns(record); err != nil {
+				return nil, err
+			}
+			if opts.MaxRange > 0 && cols[colRange] < 0 {
+				return nil, fmt.Errorf("missing CSV column: %q", "range")
+			}
+			if opts.MinSamples > 0 && cols[colSamples] < 0 {
+				return nil, fmt.Errorf("missing CSV column: %q", "samples")
+			}
+			if !opts.NoHeader {
+				continue // пропускаем первую строку с заголовком в CSV-файле
+			}
 		}
 
-		// фильруем базу данных: только GSM-станции
-		// 250 - Россия, 255 - Украина, 257 - Беларусия
-		if record[0] != "GSM" || !(record[1] == "250" || record[1] == "255" || record[1] == "257") {
+		radio, err := ParseRadio(cols.get(record, colRadio))
+		if err != nil {
+			log.Println("Bad radio:", cols.get(record, colRadio))
 			continue
 		}
-
-		id := strings.Join(record[1:3], ":") // уникальный идентификатор страны и кода оператора
-		sdb, ok := db[id]                    // получаем вложенный раздел базы
-		if !ok {
-			sdb = make(map[string][]geo.Point) // инициализируем вложенный раздел
-			db[id] = sdb
+		if !filter.radio(radio) {
+			continue
 		}
-		sid := strings.Join(record[3:5], ":") // уникальный идентификатор Cell Area и Base station number
-		stdb, ok := sdb[sid]                  // получаем доступ к массиву данных для данной станции
-		if !ok {
-			stdb = make([]geo.Point, 0)
+		mcc, err := strconv.ParseUint(cols.get(record, colMCC), 10, 16)
+		if err != nil {
+			log.Println("Bad MCC:", cols.get(record, colMCC))
+			continue
+		}
+		mnc, err := strconv.ParseUint(cols.get(record, colMNC), 10, 32)
+		if err != nil {
+			log.Println("Bad MNC:", cols.get(record, colMNC))
+			continue
+		}
+		if !filter.network(uint16(mcc), uint32(mnc)) {
+			continue
+		}
+		area, err := strconv.ParseUint(cols.get(record, colArea), 10, 32)
+		if err != nil {
+			log.Println("Bad area:", cols.get(record, colArea))
+			continue
 		}
-		lng, err := strconv.ParseFloat(record[6], 64)
+		cellID, err := strconv.ParseUint(cols.get(record, colCell), 10, 64)
 		if err != nil {
-			log.Println("Bad longitude:", record[6])
+			log.Println("Bad cell ID:", cols.get(record, colCell))
 			continue
 		}
-		lat, err := strconv.ParseFloat(record[7], 64)
+		lng, err := strconv.ParseFloat(cols.get(record, colLon), 64)
 		if err != nil {
-			log.Println("Bad latitude:", record[7])
+			log.Println("Bad longitude:", cols.get(record, colLon))
 			continue
 		}
-		sdb[sid] = append(stdb, geo.NewPoint(lat, lng))
+		lat, err := strconv.ParseFloat(cols.get(record, colLat), 64)
+		if err != nil {
+			log.Println("Bad latitude:", cols.get(record, colLat))
+			continue
+		}
+		point, err := geo.MakePoint(lat, lng)
+		if err != nil {
+			log.Println("Bad point:", lat, lng)
+			continue
+		}
+		tower, err := parseTower(cols, record)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		tower.Point = point.Fixed()
+		if (opts.MaxRange > 0 && tower.Range > opts.MaxRange) || tower.Samples < opts.MinSamples ||
+			!filter.point(point) {
+			continue
+		}
+
+		id := dbKey(radio, uint16(mcc), uint32(mnc)) // уникальный идентификатор сети, страны и кода оператора
+		sdb, ok := db[id]                            // получаем вложенный раздел базы
+		if !ok {
+			sdb = make(map[string][]Tower) // инициализируем вложенный раздел
+			db[id] = sdb
+		}
+		sid := cellKey(uint32(area), cellID) // уникальный идентификатор Cell Area и Base station number
+		sdb[sid] = append(sdb[sid], tower)
 	}
 	return db, nil
 }
+
+// parseTower разбирает необязательные атрибуты станции. Пустые значения и отсутствующие
+// колонки соответствуют нулевым значениям.
+func parseTower(cols csvColumns, record []string) (Tower, error) {
+	var (
+		tower Tower
+		err   error
+	)
+	// number разбирает числовое поле, пропуская пустые значения и значения после ошибки
+	number := func(col, bits int, signed bool) int64 {
+		value := cols.get(record, col)
+		if value == "" || err != nil {
+			return 0
+		}
+		var n int64
+		if signed {
+			n, err = strconv.ParseInt(value, 10, bits)
+		} else {
+			var u uint64
+			u, err = strconv.ParseUint(value, 10, bits)
+			n = int64(u)
+		}
+		if err != nil {
+			err = fmt.Errorf("bad %s: %s", csvColumnNames[col][0], value)
+		}
+		return n
+	}
+	tower.Unit = uint16(number(colUnit, 16, false))
+	tower.Range = uint32(number(colRange, 32, false))
+	tower.Samples = uint32(number(colSamples, 32, false))
+	tower.Changeable = number(colChangeable, 1, false) == 1
+	tower.Created = number(colCreated, 64, true)
+	tower.Updated = number(colUpdated, 64, true)
+	tower.AverageSignal = int8(number(colAverageSignal, 8, true))
+	return tower, err
+}
@@ -2,13 +2,20 @@ package lbs
 
 import (
 	"encoding/csv"
+	"encoding/gob"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/mdigger/geo"
 )
 
 func TestImportDB(t *testing.T) {
@@ -43,8 +50,8 @@ func TestFind(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	point := db.Find(request)
-	fmt.Println(point)
+	result := db.Find(request)
+	fmt.Println(result)
 }
 
 func TestFindMultiple(t *testing.T) {
@@ -116,7 +123,7 @@ func CSVDataTest(filename string) error {
 			log.Println(counter, "bad Area:", record[3])
 			continue
 		}
-		cellID, err := strconv.ParseUint(record[4], 10, 16)
+		cellID, err := strconv.ParseUint(record[4], 10, 64)
 		if err != nil {
 			log.Println(counter, "bad Cell ID:", record[4])
 			continue
@@ -136,3 +143,251 @@ func CSVDataTest(filename string) error {
 	}
 	return nil
 }
+
+func TestFindRadio(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cells.csv")
+	data := "radio,mcc,net,area,cell,unit,lon,lat,range,samples,changeable,created,updated,averageSignal\n" +
+		"GSM,250,1,560,2384,0,37.6,55.7,1000,1,1,0,0,0\n" +
+		"LTE,250,1,560,2384,0,30.3,59.9,1000,1,1,0,0,0\n" +
+		"NR,250,1,7700,68719476735,0,39.7,47.2,1000,1,1,0,0,0\n" +
+		"IDEN,250,1,1,1,0,0,0,1000,1,1,0,0,0\n"
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ImportCSV(filename)
+	if err != nil {
+		t.Fatal("Import error:", err)
+	}
+	if db.Len() != 3 {
+		t.Fatalf("bad length: %d", db.Len())
+	}
+	for _, test := range []struct {
+		cell *Cell
+		lat  float64
+	}{
+		{&Cell{Area: 560, ID: 2384, DBM: -78}, 55.7},
+		{&Cell{Radio: LTE, Area: 560, ID: 2384, DBM: -90}, 59.9},
+		{&Cell{Radio: NR, Area: 7700, ID: 1<<36 - 1, PCI: 101, EARFCN: 627264, DBM: -95}, 47.2},
+	} {
+		result := db.Find(&Request{MCC: 250, MNC: 1, Cells: []*Cell{test.cell}})
+		if math.Abs(result.Point.Lat()-test.lat) > 1e-6 {
+			t.Errorf("%v: bad result %v", test.cell.Radio, result)
+		}
+	}
+	result := db.Find(&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Radio: UMTS, Area: 560, ID: 2384}}})
+	if result.Err != ErrUnknownNetwork || !math.IsNaN(result.Point.Lat()) {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestFindUnit(t *testing.T) {
+	db := DB{
+		"LTE:250:1": {
+			"7:100": {{Point: geo.NewPoint(55.70, 37.60).Fixed(), Unit: 101}},
+			"7:200": {{Point: geo.NewPoint(55.72, 37.64).Fixed(), Unit: 55}},
+			"7:300": {{Point: geo.NewPoint(56.50, 38.50).Fixed(), Unit: 55}},
+			"8:400": {{Point: geo.NewPoint(59.90, 30.30).Fixed(), Unit: 101}},
+		},
+	}
+	serving := &Cell{Radio: LTE, Area: 7, ID: 100, DBM: -80}
+	for _, test := range []struct {
+		cells     []*Cell
+		matched   int
+		unmatched int
+		lat       [2]float64 // допустимый диапазон широты
+	}{
+		// из двух станций с PCI 55 в зоне выбирается ближайшая к обслуживающей
+		{[]*Cell{serving, {Radio: LTE, PCI: 55, EARFCN: 1300, DBM: -90}}, 2, 0, [2]float64{55.70, 55.72}},
+		{[]*Cell{{Radio: LTE, Area: 8, PCI: 101}}, 1, 0, [2]float64{59.9, 59.9}},
+		// без обслуживающей станции PCI неоднозначен или зона неизвестна
+		{[]*Cell{{Radio: LTE, Area: 7, PCI: 55}}, 0, 1, [2]float64{}},
+		{[]*Cell{{Radio: LTE, PCI: 101}}, 0, 1, [2]float64{}},
+		{[]*Cell{serving, {Radio: LTE, PCI: 77}}, 1, 1, [2]float64{55.7, 55.7}},
+	} {
+		result := db.Find(&Request{MCC: 250, MNC: 1, Cells: test.cells})
+		if result.Matched != test.matched || result.Unmatched != test.unmatched {
+			t.Errorf("%d cells: unexpected result: %+v", len(test.cells), result)
+		}
+		if test.matched > 0 && (result.Point.Lat() < test.lat[0]-1e-6 || result.Point.Lat() > test.lat[1]+1e-6) {
+			t.Errorf("%d cells: bad point %v", len(test.cells), result.Point)
+		}
+	}
+}
+
+func TestImportOptions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cells.csv")
+	data := "GSM,250,1,1,1,0,37.6,55.7,1000,10,1,0,0,0\n" +
+		"GSM,250,2,1,2,0,37.6,55.7,1000,10,1,0,0,0\n" +
+		"LTE,401,1,1,3,0,76.9,43.2,1000,10,1,0,0,0\n" +
+		"LTE,401,1,1,4,0,76.9,43.2,20000,10,1,0,0,0\n" +
+		"LTE,401,1,1,5,0,76.9,43.2,1000,1,1,0,0,0\n" +
+		"UMTS,401,1,1,6,0,71.4,51.1,1000,10,1,0,0,0\n" +
+		"GSM,255,1,1,7,0,30.5,50.4,1000,10,1,0,0,0\n"
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	almaty := geo.NewBBox(geo.NewPoint(43, 76), geo.NewPoint(44, 78))
+	for _, test := range []struct {
+		opts  ImportOptions
+		cells []uint64
+	}{
+		{ImportOptions{NoHeader: true}, []uint64{1, 2, 3, 4, 5, 6, 7}},
+		{ImportOptions{NoHeader: true, Allow: []string{"401"}}, []uint64{3, 4, 5, 6}},
+		{ImportOptions{NoHeader: true, Allow: []string{"250"}, Deny: []string{"250:2"}}, []uint64{1}},
+		{ImportOptions{NoHeader: true, Deny: []string{"250", "255"}, Radios: []Radio{LTE}}, []uint64{3, 4, 5}},
+		{ImportOptions{NoHeader: true, BBox: &almaty}, []uint64{3, 4, 5}},
+		{ImportOptions{NoHeader: true, MinSamples: 5, MaxRange: 10000}, []uint64{1, 2, 3, 6, 7}},
+	} {
+		test := test
+		db, err := ImportCSVWithOptions(filename, &test.opts)
+		if err != nil {
+			t.Fatal("Import error:", err)
+		}
+		var cells []uint64
+		for _, sdb := range db {
+			for sid := range sdb {
+				id, _ := strconv.ParseUint(sid[strings.IndexByte(sid, ':')+1:], 10, 64)
+				cells = append(cells, id)
+			}
+		}
+		sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+		if fmt.Sprint(cells) != fmt.Sprint(test.cells) {
+			t.Errorf("%+v: cells %v, want %v", test.opts, cells, test.cells)
+		}
+	}
+	if _, err := ImportCSVWithOptions(filename, &ImportOptions{Allow: []string{"250:x"}}); err == nil {
+		t.Error("expected error for bad network")
+	}
+}
+
+func TestImportHeader(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cells.csv")
+	data := "lat,lon,radio,mcc,mnc,lac,cellid,samples,range,created,updated,averageSignal,unit,changeable,comment\n" +
+		"55.7,37.6,LTE,250,1,560,2384,12,800,1459692342,1516878300,-95,101,1,x\n" +
+		"55.8,37.7,GSM,250,1,560,2385,,,,,,,,\n"
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ImportCSV(filename)
+	if err != nil {
+		t.Fatal("Import error:", err)
+	}
+	towers := db["LTE:250:1"]["560:2384"]
+	want := Tower{
+		Point:         geo.NewPoint(55.7, 37.6).Fixed(),
+		Unit:          101,
+		Range:         800,
+		Samples:       12,
+		Changeable:    true,
+		Created:       1459692342,
+		Updated:       1516878300,
+		AverageSignal: -95,
+	}
+	if len(towers) != 1 || towers[0] != want {
+		t.Errorf("bad towers: %+v", towers)
+	}
+	towers = db["GSM:250:1"]["560:2385"]
+	if len(towers) != 1 || towers[0] != (Tower{Point: geo.NewPoint(55.8, 37.7).Fixed()}) {
+		t.Errorf("bad towers: %+v", towers)
+	}
+	if err := os.WriteFile(filename, []byte("radio,mcc,net,area,cell\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportCSV(filename); err == nil {
+		t.Error("expected error for missing columns")
+	}
+}
+
+func TestFindResult(t *testing.T) {
+	db := DB{
+		"GSM:250:1": {
+			"1:1": {{Point: geo.NewPoint(55.70, 37.60).Fixed(), Range: 1000}},
+			"1:2": {{Point: geo.NewPoint(55.71, 37.62).Fixed(), Range: 1500}},
+			"1:3": {{Point: geo.NewPoint(55.69, 37.61).Fixed()}},
+			"2:1": {{Point: geo.NewPoint(59.90, 30.30).Fixed(), Range: 3000}},
+		},
+	}
+	for _, test := range []struct {
+		req       *Request
+		method    Method
+		matched   int
+		unmatched int
+		err       error
+		accuracy  [2]float64 // допустимый диапазон в метрах
+	}{
+		{nil, MethodNone, 0, 0, ErrEmptyRequest, [2]float64{0, 0}},
+		{&Request{MCC: 250, MNC: 1}, MethodNone, 0, 0, ErrNoCells, [2]float64{0, 0}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{nil, {Radio: LTE, Area: 1, PCI: 101}}},
+			MethodNone, 0, 2, ErrNoCells, [2]float64{0, 0}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Area: 2, ID: 1}, {Area: 2, PCI: 7}}},
+			MethodSingleCell, 1, 1, nil, [2]float64{3000, 3001}},
+		{&Request{MCC: 250, MNC: 2, Cells: []*Cell{{Area: 1, ID: 1}}},
+			MethodNone, 0, 1, ErrUnknownNetwork, [2]float64{0, 0}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Area: 3, ID: 1}}},
+			MethodNone, 0, 1, ErrCellsNotFound, [2]float64{0, 0}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Area: 2, ID: 1, DBM: -70}, {Area: 2, ID: 5}}},
+			MethodSingleCell, 1, 1, nil, [2]float64{3000, 3001}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Area: 1, ID: 1, DBM: -70}, {Area: 1, ID: 2, DBM: -90}}},
+			MethodRobust, 2, 0, nil, [2]float64{1000, 1500}},
+		{&Request{MCC: 250, MNC: 1, Cells: []*Cell{{Area: 1, ID: 9, DBM: -70}}},
+			MethodAreaFallback, 0, 1, nil, [2]float64{2000, 4000}},
+	} {
+		result := db.Find(test.req)
+		if result.Method != test.method || result.Matched != test.matched ||
+			result.Unmatched != test.unmatched || result.Err != test.err ||
+			result.Accuracy < test.accuracy[0] || result.Accuracy > test.accuracy[1] {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if (result.Err == nil) == math.IsNaN(result.Point.Lat()) {
+			t.Errorf("bad point: %v", result)
+		}
+	}
+	// способ вычисления определяется алгоритмом
+	req := &Request{MCC: 250, MNC: 1, Cells: []*Cell{
+		{Area: 1, ID: 1, DBM: -70}, {Area: 1, ID: 2, DBM: -90}, {Area: 2, ID: 1, DBM: -60},
+	}}
+	for _, test := range []struct {
+		estimator Estimator
+		method    Method
+		rejected  int
+	}{
+		{WeightedCentroid{}, MethodWeightedCentroid, 0},
+		{PathLoss{}, MethodPathLoss, 0},
+		{Robust{}, MethodRobust, 1},
+	} {
+		result := db.FindWith(req, test.estimator)
+		if result.Method != test.method || result.Rejected != test.rejected {
+			t.Errorf("%T: unexpected result: %+v", test.estimator, result)
+		}
+	}
+}
+
+func TestDBVersion(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "cells.gob")
+	db := DB{"GSM:250:1": {"1:1": {{Point: geo.NewPoint(55.7, 37.6).Fixed(), Range: 1000}}}}
+	if err := db.Save(filename); err != nil {
+		t.Fatal("Save error:", err)
+	}
+	loaded, err := LoadDB(filename)
+	if err != nil {
+		t.Fatal("Load error:", err)
+	}
+	if loaded.Len() != 1 || loaded["GSM:250:1"]["1:1"][0] != db["GSM:250:1"]["1:1"][0] {
+		t.Errorf("bad loaded DB: %v", loaded)
+	}
+	// файл старого формата без версии
+	old := filepath.Join(dir, "old.gob")
+	file, err := os.Create(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gob.NewEncoder(file).Encode(map[string]map[string][]geo.Point{"250:1": {"1:1": {{55.7, 37.6}}}})
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDB(old); err == nil || !strings.Contains(err.Error(), "re-import") {
+		t.Errorf("expected format error, got %v", err)
+	}
+}
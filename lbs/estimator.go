@@ -0,0 +1,223 @@
+package lbs
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mdigger/geo"
+)
+
+// Observation описывает станцию из запроса вместе с найденными для нее записями в базе данных.
+type Observation struct {
+	Cell   *Cell   // станция из запроса
+	Towers []Tower // записи о станции в базе данных
+}
+
+// Position возвращает координаты станции: если в базе есть несколько записей, то их центр,
+// взвешенный по количеству измерений.
+func (o Observation) Position() geo.Point {
+	points := make([]geo.Point, len(o.Towers))
+	weights := make([]float64, len(o.Towers))
+	for i, tower := range o.Towers {
+		points[i] = tower.Point.Point()
+		weights[i] = math.Max(1, float64(tower.Samples))
+	}
+	return centroid(points, weights)
+}
+
+// Range возвращает наибольший радиус действия станции в метрах. Если он не известен, то
+// возвращается значение по умолчанию.
+func (o Observation) Range() float64 {
+	var max uint32
+	for _, tower := range o.Towers {
+		if tower.Range > max {
+			max = tower.Range
+		}
+	}
+	if max == 0 {
+		return defaultRange
+	}
+	return float64(max)
+}
+
+// signalKnown возвращает true, если для станции известен уровень сигнала. Нулевое значение
+// Cell.DBM означает, что уровень сигнала не передан.
+func (o Observation) signalKnown() bool {
+	return o.Cell != nil && o.Cell.DBM < 0
+}
+
+// Estimator описывает алгоритм вычисления координат устройства по найденным станциям.
+// Estimate вызывается как минимум с одной станцией и возвращает вычисленные координаты и
+// станции, которые были для этого использованы: алгоритм может отбросить часть из них.
+// Method возвращает способ вычисления, который указывается в результате поиска.
+type Estimator interface {
+	Estimate(obs []Observation) (geo.Point, []Observation)
+	Method() Method
+}
+
+// DefaultEstimator используется для вычисления координат в DB.Find. Раньше DB.Find всегда
+// вычислял центр станций, взвешенный по уровню сигнала; теперь по умолчанию отбрасываются
+// выбросы, а веса считаются по модели затухания сигнала, поэтому результаты для тех же запросов
+// отличаются. Для прежнего поведения присвойте DefaultEstimator значение WeightedCentroid{}
+// или используйте DB.FindWith.
+var DefaultEstimator Estimator = Robust{}
+
+// WeightedCentroid вычисляет координаты как центр станций, взвешенный по амплитуде сигнала
+// 10^(dBm/20). Станциям с неизвестным уровнем сигнала назначается медианный вес.
+type WeightedCentroid struct{}
+
+// Method возвращает MethodWeightedCentroid.
+func (WeightedCentroid) Method() Method { return MethodWeightedCentroid }
+
+// Estimate возвращает взвешенный центр станций.
+func (WeightedCentroid) Estimate(obs []Observation) (geo.Point, []Observation) {
+	return weightedCentroid(obs, func(dbm float64) float64 {
+		return math.Pow(10, dbm/20)
+	}), obs
+}
+
+// defaultPathLossExponent задает показатель затухания сигнала для городской застройки.
+const defaultPathLossExponent = 3
+
+// PathLoss вычисляет координаты по логарифмической модели затухания сигнала: уровень сигнала
+// падает на 10*n dB при увеличении расстояния в 10 раз. По уровню сигнала оценивается
+// относительное расстояние до станции d, а ее вес в центре равен 1/d^2. Станциям с неизвестным
+// уровнем сигнала назначается медианный вес.
+type PathLoss struct {
+	// Exponent задает показатель затухания n: 2 - для открытого пространства, 2.7-3.5 - для
+	// городской застройки, 4-6 - внутри зданий. По умолчанию используется 3.
+	Exponent float64
+}
+
+// Method возвращает MethodPathLoss.
+func (PathLoss) Method() Method { return MethodPathLoss }
+
+// Estimate возвращает центр станций, взвешенный по расстоянию, оцененному по уровню сигнала.
+func (e PathLoss) Estimate(obs []Observation) (geo.Point, []Observation) {
+	n := e.Exponent
+	if n <= 0 {
+		n = defaultPathLossExponent
+	}
+	return weightedCentroid(obs, func(dbm float64) float64 {
+		// d = 10^(-dbm/(10n)), а вес 1/d^2
+		return math.Pow(10, dbm/(5*n))
+	}), obs
+}
+
+// Robust отбрасывает станции, координаты которых противоречат остальным (например,
+// перенесенные станции или станции с ошибочными данными), и вычисляет координаты по
+// оставшимся. Для каждой станции подсчитывается количество станций, зоны действия которых
+// пересекаются с ее зоной, и выбирается станция с наибольшим их количеством (при равенстве -
+// с более сильным сигналом, если он известен, иначе с большим количеством измерений или
+// меньшим радиусом действия). Остальные станции считаются выбросами.
+type Robust struct {
+	// Estimator вычисляет координаты по оставшимся станциям. По умолчанию используется PathLoss.
+	Estimator Estimator
+	// Slack задает допустимое расхождение зон действия станций в метрах.
+	Slack float64
+}
+
+// Method возвращает MethodRobust.
+func (Robust) Method() Method { return MethodRobust }
+
+// Estimate отбрасывает выбросы и возвращает координаты, вычисленные по оставшимся станциям.
+func (e Robust) Estimate(obs []Observation) (geo.Point, []Observation) {
+	estimator := e.Estimator
+	if estimator == nil {
+		estimator = PathLoss{}
+	}
+	positions := make([]geo.Point, len(obs))
+	for i, o := range obs {
+		positions[i] = o.Position()
+	}
+	inliers := func(i int) []Observation {
+		var result []Observation
+		for j, o := range obs {
+			if positions[i].Distance(positions[j])*1000 <= obs[i].Range()+o.Range()+e.Slack {
+				result = append(result, o)
+			}
+		}
+		return result
+	}
+	var best []Observation
+	bestIndex := -1
+	for i := range obs {
+		if consensus := inliers(i); len(consensus) > len(best) ||
+			(len(consensus) == len(best) && obs[i].moreReliable(obs[bestIndex])) {
+			best, bestIndex = consensus, i
+		}
+	}
+	return estimator.Estimate(best)
+}
+
+// moreReliable возвращает true, если станция o надежнее станции o2: у нее измерен уровень
+// сигнала, а у o2 нет, сигнал сильнее (если он известен у обеих), больше измерений или меньше
+// радиус действия.
+func (o Observation) moreReliable(o2 Observation) bool {
+	if o.signalKnown() != o2.signalKnown() {
+		return o.signalKnown() // станция с измеренным сигналом надежнее
+	}
+	if o.signalKnown() && o.Cell.DBM != o2.Cell.DBM {
+		return o.Cell.DBM > o2.Cell.DBM
+	}
+	if s, s2 := o.samples(), o2.samples(); s != s2 {
+		return s > s2
+	}
+	return o.Range() < o2.Range()
+}
+
+// samples возвращает общее количество измерений по всем записям о станции.
+func (o Observation) samples() uint64 {
+	var samples uint64
+	for _, tower := range o.Towers {
+		samples += uint64(tower.Samples)
+	}
+	return samples
+}
+
+// weightedCentroid возвращает центр станций с весами, вычисленными по уровню сигнала. Станции
+// с неизвестным уровнем сигнала (Cell.DBM >= 0) получают нейтральный вес - медиану весов
+// станций с известным уровнем, а если таких нет, то все станции имеют одинаковый вес.
+func weightedCentroid(obs []Observation, weight func(dbm float64) float64) geo.Point {
+	points := make([]geo.Point, len(obs))
+	weights := make([]float64, len(obs))
+	var known []float64
+	for i, o := range obs {
+		points[i] = o.Position()
+		if o.signalKnown() {
+			weights[i] = weight(float64(o.Cell.DBM))
+			known = append(known, weights[i])
+		}
+	}
+	neutral := 1.0
+	if len(known) > 0 {
+		sort.Float64s(known)
+		neutral = known[len(known)/2]
+		if len(known)%2 == 0 {
+			neutral = (known[len(known)/2-1] + neutral) / 2
+		}
+	}
+	for i, o := range obs {
+		if !o.signalKnown() {
+			weights[i] = neutral
+		}
+	}
+	return centroid(points, weights)
+}
+
+// centroid возвращает взвешенный центр точек. Точки усредняются как единичные векторы,
+// поэтому результат не зависит от перехода через антимеридиан.
+func centroid(points []geo.Point, weights []float64) geo.Point {
+	var sm, x, y, z float64
+	for i, point := range points {
+		lat, lon := point.Lat()*(math.Pi/180.0), point.Lon()*(math.Pi/180.0)
+		sm += weights[i]
+		x += weights[i] * math.Cos(lat) * math.Cos(lon)
+		y += weights[i] * math.Cos(lat) * math.Sin(lon)
+		z += weights[i] * math.Sin(lat)
+	}
+	if sm == 0 || (x == 0 && y == 0 && z == 0) {
+		return geo.NaNPoint
+	}
+	return geo.NewPoint(math.Atan2(z, math.Hypot(x, y))*(180.0/math.Pi), math.Atan2(y, x)*(180.0/math.Pi))
+}
@@ -0,0 +1,149 @@
+package lbs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mdigger/geo"
+)
+
+// estimatorFixture описывает станции вокруг известной точки. Уровень сигнала вычисляется по
+// модели затухания с показателем 3 и уровнем -10 dBm на расстоянии 1 м.
+type estimatorFixture struct {
+	name     string
+	truth    geo.Point
+	towers   []fixtureTower
+	noSignal []int // станции без уровня сигнала
+}
+
+// fixtureTower описывает станцию относительно точки: азимут, расстояние в километрах и
+// смещение записи в базе (для перенесенных станций).
+type fixtureTower struct {
+	bearing, distance float64
+	moved             float64
+}
+
+// observations возвращает станции для проверки алгоритмов.
+func (f estimatorFixture) observations() []Observation {
+	obs := make([]Observation, len(f.towers))
+	for i, t := range f.towers {
+		position := f.truth.Destination(t.bearing, t.distance)
+		dbm := -10 - 30*math.Log10(t.distance*1000)
+		if t.moved != 0 {
+			position = position.Destination(t.bearing, t.moved)
+		}
+		for _, j := range f.noSignal {
+			if i == j {
+				dbm = 0
+			}
+		}
+		obs[i] = Observation{
+			Cell:   &Cell{Radio: LTE, Area: 1, ID: uint64(i + 1), DBM: int8(math.Round(dbm))},
+			Towers: []Tower{{Point: position.Fixed(), Range: uint32(t.distance*1000) + 1000, Samples: 10}},
+		}
+	}
+	return obs
+}
+
+var estimatorFixtures = []estimatorFixture{
+	{"symmetric", geo.NewPoint(55.75, 37.62), []fixtureTower{
+		{0, 2, 0}, {90, 2, 0}, {180, 2, 0}, {270, 2, 0},
+	}, nil},
+	{"near tower", geo.NewPoint(55.75, 37.62), []fixtureTower{
+		{30, 0.3, 0}, {120, 3, 0}, {200, 4, 0}, {300, 3.5, 0},
+	}, nil},
+	{"moved tower", geo.NewPoint(43.24, 76.95), []fixtureTower{
+		{30, 0.5, 0}, {120, 2, 0}, {200, 2.5, 0}, {300, 1.5, 0}, {80, 0.4, 300},
+	}, nil},
+	{"no signal", geo.NewPoint(55.75, 37.62), []fixtureTower{
+		{30, 0.3, 0}, {120, 3, 0}, {200, 4, 0}, {300, 3.5, 0}, {60, 2.2, 0},
+	}, []int{4}},
+	{"two cells, one without signal", geo.NewPoint(59.94, 30.31), []fixtureTower{
+		{0, 0.05, 0}, {180, 2.2, 0},
+	}, []int{1}},
+}
+
+func TestEstimators(t *testing.T) {
+	estimators := []struct {
+		name      string
+		estimator Estimator
+		maxError  []float64 // допустимая ошибка для каждого набора в метрах
+		rejected  []int     // количество отброшенных станций
+	}{
+		// без отбрасывания выбросов перенесенная станция смещает координаты на 150-170 км;
+		// станция без уровня сигнала не должна перетягивать координаты к себе (до нее 2.2 км)
+		{"centroid", WeightedCentroid{}, []float64{1, 250, 160000, 300, 1100}, []int{0, 0, 0, 0, 0}},
+		{"path loss", PathLoss{Exponent: 3}, []float64{1, 300, 180000, 300, 1100}, []int{0, 0, 0, 0, 0}},
+		{"robust", Robust{}, []float64{1, 300, 400, 300, 1100}, []int{0, 0, 1, 0, 0}},
+		{"robust centroid", Robust{Estimator: WeightedCentroid{}}, []float64{1, 250, 250, 300, 1100}, []int{0, 0, 1, 0, 0}},
+	}
+	for _, e := range estimators {
+		for i, fixture := range estimatorFixtures {
+			obs := fixture.observations()
+			point, used := e.estimator.Estimate(obs)
+			distance := point.Distance(fixture.truth) * 1000
+			t.Logf("%s/%s: error %.0fm", e.name, fixture.name, distance)
+			if distance > e.maxError[i] {
+				t.Errorf("%s/%s: error %.0fm, want <= %.0fm", e.name, fixture.name, distance, e.maxError[i])
+			}
+			if len(obs)-len(used) != e.rejected[i] {
+				t.Errorf("%s/%s: rejected %d, want %d", e.name, fixture.name, len(obs)-len(used), e.rejected[i])
+			}
+		}
+	}
+}
+
+func TestRobustRejectsMovedTower(t *testing.T) {
+	fixture := estimatorFixtures[2] // moved tower
+	obs := fixture.observations()
+	weighted, _ := WeightedCentroid{}.Estimate(obs)
+	robust, _ := Robust{}.Estimate(obs)
+	// отбрасывание перенесенной станции уменьшает ошибку как минимум в 100 раз
+	if c, r := weighted.Distance(fixture.truth), robust.Distance(fixture.truth); r*100 > c {
+		t.Errorf("robust error %.0fm is not much better than centroid error %.0fm", r*1000, c*1000)
+	}
+}
+
+func TestCentroidAntimeridian(t *testing.T) {
+	points := []geo.Point{geo.NewPoint(10, 179.99), geo.NewPoint(10, -179.99)}
+	p := centroid(points, []float64{1, 1})
+	if math.Abs(p.Lat()-10) > 1e-3 || math.Abs(math.Abs(p.Lon())-180) > 1e-6 {
+		t.Errorf("bad centroid: %v", p)
+	}
+	p = Observation{Towers: []Tower{
+		{Point: geo.NewPoint(-16.5, 179.95).Fixed(), Samples: 1},
+		{Point: geo.NewPoint(-16.5, -179.97).Fixed(), Samples: 3},
+	}}.Position()
+	if p.Distance(geo.NewPoint(-16.5, -179.99)) > 0.1 {
+		t.Errorf("bad position: %v", p)
+	}
+}
+
+func TestRobustTieBreak(t *testing.T) {
+	moscow, spb := geo.NewPoint(55.75, 37.62), geo.NewPoint(59.94, 30.31)
+	observation := func(p geo.Point, dbm int8, samples, rng uint32) Observation {
+		return Observation{
+			Cell:   &Cell{Area: 1, ID: 1, DBM: dbm},
+			Towers: []Tower{{Point: p.Fixed(), Samples: samples, Range: rng}},
+		}
+	}
+	for _, test := range []struct {
+		name string
+		obs  []Observation
+		want geo.Point
+	}{
+		{"known signal", []Observation{
+			observation(moscow, 0, 10, 1000), observation(spb, -80, 10, 1000)}, spb},
+		{"stronger signal", []Observation{
+			observation(moscow, -90, 10, 1000), observation(spb, -70, 10, 1000)}, spb},
+		{"more samples", []Observation{
+			observation(moscow, 0, 50, 1000), observation(spb, 0, 10, 1000)}, moscow},
+		{"smaller range", []Observation{
+			observation(moscow, -80, 10, 3000), observation(spb, -80, 10, 1000)}, spb},
+	} {
+		point, used := Robust{}.Estimate(test.obs)
+		if len(used) != 1 || point.Distance(test.want) > 0.01 {
+			t.Errorf("%s: unexpected result %v (%d cells)", test.name, point, len(used))
+		}
+	}
+}
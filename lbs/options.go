@@ -0,0 +1,120 @@
+package lbs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mdigger/geo"
+)
+
+// ImportOptions задает фильтрацию данных при импорте базы данных из CSV. Нулевые значения
+// полей означают отсутствие соответствующего ограничения.
+type ImportOptions struct {
+	// Allow задает список разрешенных сетей в формате "MCC" (все операторы страны) или
+	// "MCC:MNC" (один оператор). Если список пустой, то разрешены все сети.
+	Allow []string
+	// Deny задает список запрещенных сетей в том же формате. Запрет имеет приоритет над
+	// разрешением.
+	Deny []string
+	// Radios задает список импортируемых типов сетей. Если список пустой, то импортируются
+	// все поддерживаемые типы.
+	Radios []Radio
+	// BBox ограничивает импорт станциями внутри указанной области.
+	BBox *geo.BBox
+	// Polygon ограничивает импорт станциями внутри указанного полигона.
+	Polygon geo.Polygon
+	// MinSamples задает минимальное количество измерений (колонка samples), по которым
+	// вычислены координаты станции.
+	MinSamples uint32
+	// MaxRange задает максимальный радиус действия станции в метрах (колонка range). Станции
+	// с большим радиусом, как правило, имеют неточные координаты.
+	MaxRange uint32
+	// NoHeader указывает, что первая строка файла содержит данные, а не заголовок.
+	NoHeader bool
+}
+
+// DefaultImportOptions используются при импорте, если параметры не заданы: импортируются
+// станции сетей России (250), Украины (255) и Беларуси (257).
+var DefaultImportOptions = ImportOptions{
+	Allow: []string{"250", "255", "257"},
+}
+
+// importFilter описывает подготовленные для быстрой проверки параметры импорта.
+type importFilter struct {
+	*ImportOptions
+	allow, deny map[string]bool
+	radios      map[Radio]bool
+}
+
+// filter проверяет параметры импорта и возвращает подготовленный фильтр.
+func (o *ImportOptions) filter() (*importFilter, error) {
+	f := &importFilter{ImportOptions: o}
+	var err error
+	if f.allow, err = networks(o.Allow); err != nil {
+		return nil, err
+	}
+	if f.deny, err = networks(o.Deny); err != nil {
+		return nil, err
+	}
+	if len(o.Radios) > 0 {
+		f.radios = make(map[Radio]bool, len(o.Radios))
+		for _, radio := range o.Radios {
+			f.radios[radio] = true
+		}
+	}
+	return f, nil
+}
+
+// networks возвращает множество сетей из списка в формате "MCC" или "MCC:MNC", приводя
+// номера к каноническому виду.
+func networks(list []string) (map[string]bool, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	set := make(map[string]bool, len(list))
+	for _, network := range list {
+		parts := strings.Split(network, ":")
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("bad network: %q", network)
+		}
+		mcc, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("bad network MCC: %q", network)
+		}
+		key := strconv.FormatUint(mcc, 10)
+		if len(parts) == 2 {
+			mnc, err := strconv.ParseUint(parts[1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("bad network MNC: %q", network)
+			}
+			key += ":" + strconv.FormatUint(mnc, 10)
+		}
+		set[key] = true
+	}
+	return set, nil
+}
+
+// network возвращает true, если сеть оператора проходит через списки разрешенных и
+// запрещенных сетей.
+func (f *importFilter) network(mcc uint16, mnc uint32) bool {
+	country := strconv.FormatUint(uint64(mcc), 10)
+	operator := fmt.Sprintf("%s:%d", country, mnc)
+	if f.deny[country] || f.deny[operator] {
+		return false
+	}
+	return f.allow == nil || f.allow[country] || f.allow[operator]
+}
+
+// radio возвращает true, если тип сети разрешен для импорта.
+func (f *importFilter) radio(radio Radio) bool {
+	return f.radios == nil || f.radios[radio]
+}
+
+// point возвращает true, если точка попадает в заданную область и полигон.
+func (f *importFilter) point(p geo.Point) bool {
+	if f.BBox != nil && !f.BBox.Contains(p) {
+		return false
+	}
+	return f.Polygon == nil || f.Polygon.Contains(p)
+}
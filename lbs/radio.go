@@ -0,0 +1,45 @@
+package lbs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Radio описывает тип радиосети базовой станции.
+type Radio uint8
+
+// Поддерживаемые типы радиосетей. Нулевое значение соответствует GSM для совместимости
+// с запросами, в которых тип сети не указан.
+const (
+	GSM  Radio = iota // 2G
+	UMTS              // 3G (WCDMA)
+	LTE               // 4G
+	NR                // 5G New Radio
+	CDMA              // CDMA2000
+)
+
+var radioNames = [...]string{GSM: "GSM", UMTS: "UMTS", LTE: "LTE", NR: "NR", CDMA: "CDMA"}
+
+// String возвращает название типа сети в том виде, в котором оно используется в OpenCelliD
+// и Mozilla Location Service.
+func (r Radio) String() string {
+	if int(r) < len(radioNames) {
+		return radioNames[r]
+	}
+	return fmt.Sprintf("Radio(%d)", r)
+}
+
+// ParseRadio возвращает тип сети по его названию. Регистр не учитывается, а WCDMA считается
+// синонимом UMTS.
+func ParseRadio(s string) (Radio, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "WCDMA" {
+		return UMTS, nil
+	}
+	for r, name := range radioNames {
+		if s == name {
+			return Radio(r), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown radio: %q", s)
+}
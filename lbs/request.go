@@ -8,10 +8,18 @@ import (
 )
 
 // Cell описывает информацию о базовой станции и уровне сигнала.
+//
+// Значение полей Area и ID зависит от типа сети: для GSM и UMTS это LAC и CID (для UMTS - 28-битный
+// UTRAN Cell ID), для LTE - TAC и 28-битный ECI (eNodeB ID * 256 + номер сектора), для NR - TAC
+// и 36-битный NCI, для CDMA - NID и BID. Для соседних станций LTE и NR часто известны только PCI
+// и EARFCN (ID == 0): такие станции ищутся по PCI среди станций той же зоны (см. DB.FindWith).
 type Cell struct {
-	Area uint32 // lac - the base station cell number
-	ID   uint16 // base station number
-	DBM  int8   // signal strength ((dbm + 110 = rxlev + 110 = watch sign strength)
+	Radio  Radio  // radio type (GSM by default)
+	Area   uint32 // lac/tac - the base station cell number
+	ID     uint64 // base station number (cid, eci, nci)
+	PCI    uint16 // physical cell id (LTE, NR) or primary scrambling code (UMTS)
+	EARFCN uint32 // radio channel number (arfcn, uarfcn, earfcn, nr-arfcn)
+	DBM    int8   // signal strength ((dbm + 110 = rxlev + 110 = watch sign strength)
 }
 
 // Request описывает информацию о запросе в формате LBS.
@@ -25,6 +33,10 @@ type Request struct {
 }
 
 // Parse разбирает строку с информацией в формате LBS и возвращает его описание.
+//
+// Трекеры передают в этом формате только данные о станциях GSM: после MCC и MNC следуют тройки
+// area-id-signal в шестнадцатеричном виде, поэтому тип сети у всех станций - GSM, а PCI и
+// EARFCN не заполняются. Неполная тройка в конце строки игнорируется.
 func Parse(s string) (*Request, error) {
 	splitted := strings.Split(s, "-") // разделяем на элементы
 	if len(splitted) < 7 {
@@ -48,7 +60,7 @@ func Parse(s string) (*Request, error) {
 		if err != nil {
 			return nil, fmt.Errorf("bad Area: %s", splitted[5+i*3])
 		}
-		id, err := strconv.ParseUint(splitted[6+i*3], 16, 16)
+		id, err := strconv.ParseUint(splitted[6+i*3], 16, 64)
 		if err != nil {
 			return nil, fmt.Errorf("bad Cell ID: %s", splitted[6+i*3])
 		}
@@ -57,9 +69,10 @@ func Parse(s string) (*Request, error) {
 			return nil, fmt.Errorf("bad DBM: %s", splitted[7+i*3])
 		}
 		cells[i] = &Cell{
-			Area: uint32(area),
-			ID:   uint16(id),
-			DBM:  int8(dbm - 220),
+			Radio: GSM,
+			Area:  uint32(area),
+			ID:    id,
+			DBM:   int8(dbm - 220),
 		}
 	}
 	return &Request{
@@ -15,3 +15,25 @@ func TestParse(t *testing.T) {
 	}
 	pretty.Println(data)
 }
+
+func TestParseCells(t *testing.T) {
+	data, err := Parse("864078-35827-010003698-fa-1-1e50-772a-95-1e50-1a2b3c4-a0-7700")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Cell{
+		{Radio: GSM, Area: 0x1e50, ID: 0x772a, DBM: -71},
+		{Radio: GSM, Area: 0x1e50, ID: 0x1a2b3c4, DBM: -60},
+	}
+	if data.MCC != 250 || data.MNC != 1 || len(data.Cells) != len(want) {
+		t.Fatalf("bad request: %+v", data)
+	}
+	for i, cell := range data.Cells {
+		if *cell != want[i] {
+			t.Errorf("bad cell %d: %+v, want %+v", i, *cell, want[i])
+		}
+	}
+	if _, err := Parse("864078-35827-010003698-fa-1-LTE-1e50-772a-95"); err == nil {
+		t.Error("expected error for radio type")
+	}
+}
@@ -0,0 +1,83 @@
+package lbs
+
+import (
+	"fmt"
+
+	"github.com/mdigger/geo"
+)
+
+// Method описывает способ, которым были вычислены координаты.
+type Method uint8
+
+// Способы вычисления координат.
+const (
+	MethodNone             Method = iota // координаты не определены
+	MethodSingleCell                     // по координатам одной станции
+	MethodWeightedCentroid               // взвешенный по уровню сигнала центр нескольких станций
+	MethodAreaFallback                   // центр всех известных станций той же зоны (LAC/TAC)
+	MethodPathLoss                       // центр станций, взвешенный по модели затухания сигнала
+	MethodRobust                         // по станциям, оставшимся после отбрасывания выбросов
+)
+
+var methodNames = [...]string{
+	MethodNone:             "none",
+	MethodSingleCell:       "single cell",
+	MethodWeightedCentroid: "weighted centroid",
+	MethodAreaFallback:     "area fallback",
+	MethodPathLoss:         "path loss",
+	MethodRobust:           "robust",
+}
+
+// String возвращает название способа вычисления координат.
+func (m Method) String() string {
+	if int(m) < len(methodNames) {
+		return methodNames[m]
+	}
+	return fmt.Sprintf("Method(%d)", m)
+}
+
+// FindError описывает причину, по которой не удалось определить координаты.
+type FindError uint8
+
+// Причины, по которым не удалось определить координаты.
+const (
+	ErrEmptyRequest   FindError = iota + 1 // запрос или база данных не заданы
+	ErrNoCells                             // в запросе нет станций с идентификаторами
+	ErrUnknownNetwork                      // в базе нет данных об операторе
+	ErrCellsNotFound                       // в базе нет ни станций, ни их зон
+)
+
+var findErrorNames = [...]string{
+	ErrEmptyRequest:   "empty request",
+	ErrNoCells:        "no cells in request",
+	ErrUnknownNetwork: "unknown network",
+	ErrCellsNotFound:  "cells not found",
+}
+
+// Error возвращает описание ошибки.
+func (e FindError) Error() string {
+	if e > 0 && int(e) < len(findErrorNames) {
+		return "lbs: " + findErrorNames[e]
+	}
+	return fmt.Sprintf("lbs: find error %d", uint8(e))
+}
+
+// Result описывает результат определения координат по базовым станциям.
+type Result struct {
+	Point     geo.Point // вычисленные координаты или geo.NaNPoint
+	Accuracy  float64   // радиус области, в которой находится устройство, в метрах
+	Matched   int       // количество станций запроса, найденных в базе
+	Unmatched int       // количество станций запроса, не найденных в базе или без идентификатора
+	Rejected  int       // количество найденных станций, отброшенных как выбросы
+	Method    Method    // способ вычисления координат
+	Err       error     // причина, по которой координаты не определены (FindError)
+}
+
+// String возвращает строковое представление результата.
+func (r *Result) String() string {
+	if r.Err != nil {
+		return r.Err.Error()
+	}
+	return fmt.Sprintf("%v ±%.0fm (%v, %d/%d cells)", r.Point, r.Accuracy, r.Method,
+		r.Matched, r.Matched+r.Unmatched)
+}
@@ -0,0 +1,109 @@
+package lbs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mdigger/geo"
+)
+
+// Tower описывает запись о базовой станции в базе данных с атрибутами, позволяющими оценить
+// качество ее координат.
+type Tower struct {
+	Point         geo.FixedPoint // вычисленные координаты станции
+	Unit          uint16         // PSC (UMTS) или PCI (LTE, NR)
+	Range         uint32         // оценка радиуса действия станции в метрах
+	Samples       uint32         // количество измерений, по которым вычислены координаты
+	Changeable    bool           // координаты вычислены по измерениям, а не получены от оператора
+	Created       int64          // время первого измерения (Unix time, секунды)
+	Updated       int64          // время последнего измерения (Unix time, секунды)
+	AverageSignal int8           // средний уровень сигнала в dBm (0 - неизвестен)
+}
+
+// Колонки CSV-файла с данными о станциях.
+const (
+	colRadio = iota
+	colMCC
+	colMNC
+	colArea
+	colCell
+	colUnit
+	colLon
+	colLat
+	colRange
+	colSamples
+	colChangeable
+	colCreated
+	colUpdated
+	colAverageSignal
+	colCount // количество известных колонок
+)
+
+// csvColumnNames задает названия колонок в заголовке CSV-файла в нижнем регистре. Первое
+// название используется в OpenCelliD и Mozilla Location Service, остальные - синонимы
+// из других выгрузок. Порядок колонок соответствует порядку в выгрузке OpenCelliD.
+var csvColumnNames = [colCount][]string{
+	colRadio:         {"radio", "act"},
+	colMCC:           {"mcc"},
+	colMNC:           {"net", "mnc"},
+	colArea:          {"area", "lac", "tac"},
+	colCell:          {"cell", "cellid", "cid"},
+	colUnit:          {"unit", "psc", "pci"},
+	colLon:           {"lon", "lng", "long"},
+	colLat:           {"lat"},
+	colRange:         {"range"},
+	colSamples:       {"samples"},
+	colChangeable:    {"changeable"},
+	colCreated:       {"created"},
+	colUpdated:       {"updated"},
+	colAverageSignal: {"averagesignal", "average_signal"},
+}
+
+// csvColumns описывает номера колонок CSV-файла для каждого поля или -1, если колонки нет.
+type csvColumns [colCount]int
+
+// defaultColumns возвращает номера колонок для файла без заголовка в формате OpenCelliD.
+func defaultColumns(fields int) csvColumns {
+	var cols csvColumns
+	for i := range cols {
+		cols[i] = i
+		if i >= fields {
+			cols[i] = -1
+		}
+	}
+	return cols
+}
+
+// headerColumns возвращает номера колонок по заголовку CSV-файла. Колонки с неизвестными
+// названиями игнорируются; если нет одной из обязательных колонок (тип сети, MCC, MNC, area,
+// cell, lon, lat), то возвращается ошибка.
+func headerColumns(header []string) (csvColumns, error) {
+	var cols csvColumns
+	for i := range cols {
+		cols[i] = -1
+	}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for col, names := range csvColumnNames {
+			for _, n := range names {
+				if n == name && cols[col] < 0 {
+					cols[col] = i
+				}
+			}
+		}
+	}
+	for col := colRadio; col <= colLat; col++ {
+		if col != colUnit && cols[col] < 0 {
+			return cols, fmt.Errorf("missing CSV column: %q", csvColumnNames[col][0])
+		}
+	}
+	return cols, nil
+}
+
+// get возвращает значение поля из записи или пустую строку, если колонки нет.
+func (c csvColumns) get(record []string, col int) string {
+	if c[col] < 0 || c[col] >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[c[col]])
+}
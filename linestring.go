@@ -0,0 +1,242 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+)
+
+// LineString описывает ломаную линию (трек) из последовательных точек, соединенных дугами
+// большого круга. Все расстояния, кроме допусков упрощения, задаются в километрах.
+type LineString []Point
+
+// Length возвращает длину линии в километрах.
+func (ls LineString) Length() float64 {
+	var length float64
+	for i := 1; i < len(ls); i++ {
+		length += ls[i-1].Distance(ls[i])
+	}
+	return length
+}
+
+// Interpolate возвращает точку, находящуюся на указанном расстоянии в километрах от начала
+// линии. Расстояния за пределами линии ограничиваются ее начальной и конечной точкой.
+func (ls LineString) Interpolate(distance float64) Point {
+	if len(ls) == 0 {
+		return NaNPoint
+	}
+	if distance <= 0 {
+		return ls[0]
+	}
+	for i := 1; i < len(ls); i++ {
+		d := ls[i-1].Distance(ls[i])
+		if distance <= d {
+			return ls[i-1].Intermediate(ls[i], distance/d)
+		}
+		distance -= d
+	}
+	return ls[len(ls)-1]
+}
+
+// Resample возвращает линию, точки которой расположены на равном расстоянии spacing
+// в километрах друг от друга вдоль исходной линии. Первая и последняя точки сохраняются.
+func (ls LineString) Resample(spacing float64) LineString {
+	if len(ls) < 2 || !(spacing > 0) {
+		return append(LineString(nil), ls...)
+	}
+	result := LineString{ls[0]}
+	var next = spacing // расстояние от начала линии до следующей точки
+	var offset float64 // расстояние от начала линии до начала текущего отрезка
+	for i := 1; i < len(ls); i++ {
+		d := ls[i-1].Distance(ls[i])
+		for next < offset+d {
+			result = append(result, ls[i-1].Intermediate(ls[i], (next-offset)/d))
+			next += spacing
+		}
+		offset += d
+	}
+	if last := ls[len(ls)-1]; result[len(result)-1] != last {
+		result = append(result, last)
+	}
+	return result
+}
+
+// locate находит ближайшую к точке позицию на линии и возвращает расстояние до нее со знаком
+// (положительное - справа по ходу движения) и расстояние от начала линии до этой позиции.
+func (ls LineString) locate(p Point) (crossTrack, alongTrack float64) {
+	switch len(ls) {
+	case 0:
+		return math.NaN(), math.NaN()
+	case 1:
+		return p.Distance(ls[0]), 0
+	}
+	pv := toVector(p)
+	crossTrack = math.Inf(1)
+	var offset float64 // расстояние от начала линии до начала текущего отрезка
+	for i := 1; i < len(ls); i++ {
+		a, b := toVector(ls[i-1]), toVector(ls[i])
+		segment := a.angle(b) * erath_radius
+		n := a.cross(b)
+		var dist, along float64
+		if n.norm() == 0 { // отрезок вырожден в точку
+			dist = pv.angle(a) * erath_radius
+		} else {
+			n = n.normalize()
+			proj := pv.add(n.scale(-pv.dot(n)))
+			switch {
+			case a.cross(proj).dot(n) < 0: // проекция до начала отрезка
+				dist = pv.angle(a) * erath_radius
+			case proj.cross(b).dot(n) < 0: // проекция после конца отрезка
+				dist, along = pv.angle(b)*erath_radius, segment
+			default:
+				dist = math.Abs(math.Asin(math.Max(-1, math.Min(1, pv.dot(n))))) * erath_radius
+				along = a.angle(proj) * erath_radius
+			}
+			if pv.dot(n) > 0 { // точка слева от направления движения
+				dist = -dist
+			}
+		}
+		if math.Abs(dist) < math.Abs(crossTrack) {
+			crossTrack, alongTrack = dist, offset+along
+		}
+		offset += segment
+	}
+	return crossTrack, alongTrack
+}
+
+// CrossTrackDistance возвращает расстояние в километрах от точки до ближайшего отрезка линии.
+// Расстояние положительное, если точка находится справа по ходу движения, и отрицательное,
+// если слева.
+func (ls LineString) CrossTrackDistance(p Point) float64 {
+	crossTrack, _ := ls.locate(p)
+	return crossTrack
+}
+
+// AlongTrackDistance возвращает расстояние в километрах вдоль линии от ее начала до ближайшей
+// к точке позиции на линии.
+func (ls LineString) AlongTrackDistance(p Point) float64 {
+	_, alongTrack := ls.locate(p)
+	return alongTrack
+}
+
+// SimplifyDouglasPeucker возвращает упрощенную по алгоритму Дугласа-Пекера линию: точки,
+// отклоняющиеся от упрощенной линии меньше, чем на tolerance метров, удаляются.
+func (ls LineString) SimplifyDouglasPeucker(tolerance float64) LineString {
+	if len(ls) < 3 {
+		return append(LineString(nil), ls...)
+	}
+	vs := make([]vector, len(ls))
+	for i, p := range ls {
+		vs[i] = toVector(p)
+	}
+	keep := make([]bool, len(ls))
+	keep[0], keep[len(ls)-1] = true, true
+	stack := [][2]int{{0, len(ls) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		var maxDist float64
+		var index int
+		for i := first + 1; i < last; i++ {
+			if d := arcDistance(vs[i], vs[first], vs[last]) * 1000; d > maxDist {
+				maxDist, index = d, i
+			}
+		}
+		if maxDist > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+	var result LineString
+	for i, p := range ls {
+		if keep[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// SimplifyVisvalingam возвращает упрощенную по алгоритму Висвалингам-Уайатта линию: точки
+// удаляются, пока площадь треугольника, образованного точкой и ее соседями, меньше квадрата
+// tolerance. Допуск задается в метрах, как и в SimplifyDouglasPeucker, но сравнивается с
+// площадью: например, зигзаг с отклонением h на участке длиной l удаляется при tolerance
+// больше sqrt(h*l/2).
+func (ls LineString) SimplifyVisvalingam(tolerance float64) LineString {
+	if len(ls) < 3 {
+		return append(LineString(nil), ls...)
+	}
+	threshold := tolerance * tolerance // пороговая площадь в квадратных метрах
+	vs := make([]vector, len(ls))
+	for i, p := range ls {
+		vs[i] = toVector(p)
+	}
+	items := make([]*vwItem, len(ls))
+	for i := range items {
+		items[i] = &vwItem{index: i, prev: i - 1, next: i + 1}
+	}
+	area := func(item *vwItem) float64 {
+		a, b, c := vs[item.prev], vs[item.index], vs[item.next]
+		e := 2 * math.Atan2(math.Abs(a.dot(b.cross(c))), 1+a.dot(b)+b.dot(c)+c.dot(a))
+		return e * erath_radius * erath_radius * 1e6
+	}
+	h := make(vwHeap, 0, len(ls)-2)
+	for _, item := range items[1 : len(ls)-1] {
+		item.area, item.heapIndex = area(item), len(h)
+		h = append(h, item)
+	}
+	heap.Init(&h)
+	removed := make([]bool, len(ls))
+	for h.Len() > 0 {
+		item := heap.Pop(&h).(*vwItem)
+		if item.area >= threshold {
+			break
+		}
+		removed[item.index] = true
+		prev, next := items[item.prev], items[item.next]
+		prev.next, next.prev = next.index, prev.index
+		// площадь соседей не может стать меньше площади удаленной точки
+		for _, neighbor := range []*vwItem{prev, next} {
+			if neighbor.index == 0 || neighbor.index == len(ls)-1 {
+				continue
+			}
+			neighbor.area = math.Max(area(neighbor), item.area)
+			heap.Fix(&h, neighbor.heapIndex)
+		}
+	}
+	var result LineString
+	for i, p := range ls {
+		if !removed[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// vwItem описывает точку линии при упрощении по алгоритму Висвалингам-Уайатта.
+type vwItem struct {
+	index, prev, next int     // индексы точки и ее текущих соседей
+	area              float64 // эффективная площадь треугольника
+	heapIndex         int     // позиция в куче
+}
+
+// vwHeap описывает кучу точек, упорядоченную по возрастанию эффективной площади.
+type vwHeap []*vwItem
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex, h[j].heapIndex = i, j
+}
+
+func (h *vwHeap) Push(x interface{}) {
+	item := x.(*vwItem)
+	item.heapIndex = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
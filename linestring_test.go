@@ -0,0 +1,83 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLineString(t *testing.T) {
+	// линия по экватору длиной 2 градуса с изломом
+	ls := LineString{{0, 0}, {0, 1}, {1, 1}}
+	deg := math.Pi * erath_radius / 180 // длина одного градуса по большому кругу
+	if l := ls.Length(); math.Abs(l-2*deg) > 1e-9 {
+		t.Errorf("bad length: %f", l)
+	}
+	if p := ls.Interpolate(deg / 2); p.Distance(NewPoint(0, 0.5)) > 1e-9 {
+		t.Errorf("bad interpolation: %v", p)
+	}
+	if p := ls.Interpolate(1.5 * deg); p.Distance(NewPoint(0.5, 1)) > 1e-6 {
+		t.Errorf("bad interpolation: %v", p)
+	}
+	if p := ls.Interpolate(10 * deg); p != ls[2] {
+		t.Errorf("bad interpolation beyond end: %v", p)
+	}
+	r := ls.Resample(deg / 4)
+	if len(r) != 9 || r[0] != ls[0] || r[8] != ls[2] {
+		t.Fatalf("bad resample: %v", r)
+	}
+	for i := 1; i < len(r); i++ {
+		if d := r[i-1].Distance(r[i]); math.Abs(d-deg/4) > 1e-6 {
+			t.Errorf("bad resample spacing: %f", d)
+		}
+	}
+	// точка севернее первого отрезка: слева по ходу движения на восток
+	p := NewPoint(0.1, 0.5)
+	if d := ls.CrossTrackDistance(p); math.Abs(d+0.1*deg) > 1e-6 {
+		t.Errorf("bad cross-track distance: %f", d)
+	}
+	if d := ls.AlongTrackDistance(p); math.Abs(d-0.5*deg) > 1e-3 {
+		t.Errorf("bad along-track distance: %f", d)
+	}
+	// точка восточнее второго отрезка: справа по ходу движения на север
+	p = NewPoint(0.5, 1.2)
+	if d := ls.CrossTrackDistance(p); math.Abs(d-p.Distance(NewPoint(0.5, 1))) > 1e-3 {
+		t.Errorf("bad cross-track distance: %f", d)
+	}
+	if d := ls.AlongTrackDistance(p); math.Abs(d-1.5*deg) > 1e-3 {
+		t.Errorf("bad along-track distance: %f", d)
+	}
+}
+
+func TestLineStringSimplify(t *testing.T) {
+	// зигзаг вдоль экватора с шагом 0.001° (111.2 м) и отклонением 0.0001° (11.12 м):
+	// треугольники зигзага имеют площадь 11.12 * 222.4 / 2 = 1236 квадратных метров
+	var ls LineString
+	for i := 0; i <= 100; i++ {
+		var lat float64
+		if i%2 == 1 {
+			lat = 0.0001
+		}
+		ls = append(ls, NewPoint(lat, float64(i)*0.001))
+	}
+	ls = append(ls, NewPoint(1, 0.1)) // значимый поворот
+	for _, test := range []struct {
+		name         string
+		simplify     func(float64) LineString
+		keep, remove float64
+	}{
+		{"DouglasPeucker", ls.SimplifyDouglasPeucker, 11, 11.3},
+		{"Visvalingam", ls.SimplifyVisvalingam, 35, 35.3}, // sqrt(1236) = 35.16
+	} {
+		if s := test.simplify(test.keep); len(s) <= 3 {
+			t.Errorf("%s: tolerance %f removed zigzag: %v", test.name, test.keep, s)
+		}
+		s := test.simplify(test.remove)
+		if len(s) != 3 || s[0] != ls[0] || s[1] != ls[100] || s[2] != ls[len(ls)-1] {
+			t.Errorf("%s: bad simplification with tolerance %f: %v", test.name, test.remove, s)
+		}
+	}
+	// все треугольники зигзага одинаковы, поэтому Висвалингам не удаляет ни одной точки
+	if s := ls.SimplifyVisvalingam(35); len(s) != len(ls) {
+		t.Errorf("Visvalingam: removed %d points", len(ls)-len(s))
+	}
+}
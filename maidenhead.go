@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maidenheadDivisions описывает деление ячейки Maidenhead на каждом уровне по долготе и широте:
+// поле (буквы A-R), квадрат (цифры), подквадрат (буквы a-x) и расширенный квадрат (цифры).
+var maidenheadDivisions = [...]int{18, 10, 24, 10}
+
+// Maidenhead возвращает QTH-локатор Maidenhead, используемый радиолюбителями, длиной 2, 4,
+// 6 или 8 символов, например "KO85ts" для Москвы. Буквы подквадрата записываются в нижнем
+// регистре.
+func (p Point) Maidenhead(length int) (string, error) {
+	if length < 2 || length > 2*len(maidenheadDivisions) || length%2 == 1 {
+		return "", fmt.Errorf("bad Maidenhead locator length: %d", length)
+	}
+	if !(p.Lat() >= -90 && p.Lat() <= 90) {
+		return "", ErrBadLatitude
+	}
+	if !(p.Lon() >= -180 && p.Lon() <= 180) {
+		return "", ErrBadLongitude
+	}
+	// долю от полного диапазона уточняем на каждом уровне, северный полюс и 180° долготы
+	// относятся к крайним ячейкам
+	lat := math.Min((p.Lat()+90)/180, math.Nextafter(1, 0))
+	lon := math.Min((p.Lon()+180)/360, math.Nextafter(1, 0))
+	locator := make([]byte, 0, length)
+	for level := 0; level < length/2; level++ {
+		n := float64(maidenheadDivisions[level])
+		lon *= n
+		lat *= n
+		x, y := math.Floor(lon), math.Floor(lat)
+		lon, lat = lon-x, lat-y
+		var base byte
+		switch level {
+		case 0:
+			base = 'A'
+		case 2:
+			base = 'a'
+		default:
+			base = '0'
+		}
+		locator = append(locator, base+byte(x), base+byte(y))
+	}
+	return string(locator), nil
+}
+
+// MaidenheadBBox возвращает область, описываемую QTH-локатором Maidenhead. Регистр букв
+// не учитывается.
+func MaidenheadBBox(locator string) (BBox, error) {
+	if len(locator) < 2 || len(locator) > 2*len(maidenheadDivisions) || len(locator)%2 == 1 {
+		return EmptyBBox, fmt.Errorf("bad Maidenhead locator: %q", locator)
+	}
+	loc := strings.ToUpper(locator)
+	// номер ячейки и их общее количество по каждой оси на последнем уровне
+	var x, y int
+	n := 1
+	for level := 0; level < len(loc)/2; level++ {
+		div := maidenheadDivisions[level]
+		base := byte('0')
+		if level%2 == 0 {
+			base = 'A'
+		}
+		dx, dy := int(loc[level*2])-int(base), int(loc[level*2+1])-int(base)
+		if dx < 0 || dx >= div || dy < 0 || dy >= div {
+			return EmptyBBox, fmt.Errorf("bad Maidenhead locator: %q", locator)
+		}
+		x, y, n = x*div+dx, y*div+dy, n*div
+	}
+	return BBox{
+		South: float64(y)*180/float64(n) - 90,
+		West:  float64(x)*360/float64(n) - 180,
+		North: float64(y+1)*180/float64(n) - 90,
+		East:  float64(x+1)*360/float64(n) - 180,
+	}, nil
+}
+
+// DecodeMaidenhead возвращает центр области, описываемой QTH-локатором Maidenhead.
+func DecodeMaidenhead(locator string) (Point, error) {
+	b, err := MaidenheadBBox(locator)
+	if err != nil {
+		return NaNPoint, err
+	}
+	return b.Center(), nil
+}
@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMaidenhead(t *testing.T) {
+	for _, test := range []struct {
+		point   Point
+		locator string
+	}{
+		{Point{55.7539, 37.6208}, "KO85ts40"},    // Москва
+		{Point{41.714775, -72.727260}, "FN31pr"}, // W1AW
+		{Point{-33.8568, 151.2153}, "QF56od"},
+		{Point{0, 0}, "JJ00aa00"},
+		{Point{-90, -180}, "AA00aa00"},
+		{Point{90, 180}, "RR99xx99"},
+	} {
+		locator, err := test.point.Maidenhead(len(test.locator))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locator != test.locator {
+			t.Errorf("%v: %s, want %s", test.point, locator, test.locator)
+		}
+		// более короткие локаторы являются префиксами
+		for length := 2; length < len(test.locator); length += 2 {
+			if short, _ := test.point.Maidenhead(length); short != test.locator[:length] {
+				t.Errorf("%v: %s is not a prefix of %s", test.point, short, test.locator)
+			}
+		}
+		b, err := MaidenheadBBox(strings.ToLower(locator))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !b.Contains(test.point) {
+			t.Errorf("%s: %v does not contain %v", locator, b, test.point)
+		}
+	}
+	b, err := MaidenheadBBox("KO85")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b != (BBox{South: 55, West: 36, North: 56, East: 38}) {
+		t.Errorf("bad bbox: %v", b)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		locator, _ := p.Maidenhead(8)
+		center, err := DecodeMaidenhead(locator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// размер расширенного квадрата 30" по долготе и 15" по широте
+		if d := p.Distance(center); d > 0.5 {
+			t.Errorf("%v -> %s -> %v (%f km)", p, locator, center, d)
+		}
+	}
+	for _, locator := range []string{"", "K", "KO8", "SO85", "KOA5", "KO85yy", "KO85ts4a", "KO85ts4000"} {
+		if _, err := MaidenheadBBox(locator); err == nil {
+			t.Errorf("%q: expected error", locator)
+		}
+	}
+	if _, err := (Point{0, 0}).Maidenhead(5); err == nil {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Каноническое представление точки:
+//
+//	JSON:   {"lat":55.7558,"lon":37.6173}, пустая точка (NaNPoint) - null
+//	Текст:  55.7558,37.6173, пустая точка - пустая строка
+//	Binary: 16 байт - широта и долгота в формате float64 (IEEE 754, big-endian)
+//
+// При разборе JSON кроме объекта так же поддерживается массив GeoJSON в формате [lon,lat],
+// а при разборе текста - все форматы, поддерживаемые ParsePoint.
+
+// isNaN возвращает true, если точка пустая.
+func (p Point) isNaN() bool {
+	return math.IsNaN(p[0]) || math.IsNaN(p[1])
+}
+
+// jsonPoint описывает представление точки в формате JSON.
+type jsonPoint struct {
+	Lat *float64 `json:"lat"`
+	Lon *float64 `json:"lon"`
+}
+
+// MarshalJSON возвращает представление точки в формате JSON.
+func (p Point) MarshalJSON() ([]byte, error) {
+	if p.isNaN() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(jsonPoint{Lat: &p[0], Lon: &p[1]})
+}
+
+// UnmarshalJSON восстанавливает точку из JSON-объекта {"lat":..,"lon":..} или массива
+// GeoJSON [lon,lat]. Значение null соответствует NaNPoint.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*p = NaNPoint
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var coords []float64
+		if err := json.Unmarshal(data, &coords); err != nil {
+			return err
+		}
+		// допускается третья координата с высотой, которая игнорируется
+		if len(coords) < 2 || len(coords) > 3 {
+			return fmt.Errorf("bad GeoJSON position: %s", data)
+		}
+		point, err := MakePoint(coords[1], coords[0])
+		if err != nil {
+			return err
+		}
+		*p = point
+		return nil
+	}
+	var jp jsonPoint
+	if err := json.Unmarshal(data, &jp); err != nil {
+		return err
+	}
+	if jp.Lat == nil || jp.Lon == nil {
+		return fmt.Errorf("bad point: %s", data)
+	}
+	point, err := MakePoint(*jp.Lat, *jp.Lon)
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
+
+// MarshalText возвращает текстовое представление точки в формате "lat,lon".
+func (p Point) MarshalText() ([]byte, error) {
+	if p.isNaN() {
+		return []byte{}, nil
+	}
+	text := strconv.AppendFloat(nil, p[0], 'f', -1, 64)
+	text = append(text, ',')
+	return strconv.AppendFloat(text, p[1], 'f', -1, 64), nil
+}
+
+// UnmarshalText восстанавливает точку из текстового представления. Пустая строка соответствует
+// NaNPoint.
+func (p *Point) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*p = NaNPoint
+		return nil
+	}
+	point, err := ParsePoint(string(text))
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
+
+// pointBinaryVersion задает версию бинарного представления точки.
+const pointBinaryVersion = 1
+
+// MarshalBinary возвращает бинарное представление точки: байт версии формата, за которым идут
+// широта и долгота в виде float64 (big-endian). Это представление используется так же и
+// encoding/gob.
+//
+// Внимание: до появления этого метода gob сохранял Point как массив [2]float64. Это изменение
+// формата затрагивает и все типы, содержащие Point (структуры, срезы, словари): данные gob,
+// записанные ранее, не совместимы с текущим форматом и не могут быть прочитаны напрямую. Их
+// нужно декодировать в тип, где вместо Point используется [2]float64, и затем преобразовать.
+func (p Point) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 17)
+	data[0] = pointBinaryVersion
+	binary.BigEndian.PutUint64(data[1:], math.Float64bits(p[0]))
+	binary.BigEndian.PutUint64(data[9:], math.Float64bits(p[1]))
+	return data, nil
+}
+
+// UnmarshalBinary восстанавливает точку из бинарного представления. Поддерживается так же
+// первоначальный формат без байта версии (16 байт). Координаты проверяются так же, как в
+// MakePoint; пустая точка (NaNPoint) допустима.
+func (p *Point) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) == 17 && data[0] == pointBinaryVersion:
+		data = data[1:]
+	case len(data) == 17:
+		return fmt.Errorf("unsupported binary point version: %d", data[0])
+	case len(data) != 16:
+		return errors.New("bad binary point length")
+	}
+	lat := math.Float64frombits(binary.BigEndian.Uint64(data))
+	lon := math.Float64frombits(binary.BigEndian.Uint64(data[8:]))
+	if math.IsNaN(lat) && math.IsNaN(lon) {
+		*p = NaNPoint
+		return nil
+	}
+	point, err := MakePoint(lat, lon)
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
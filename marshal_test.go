@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestPointJSON(t *testing.T) {
+	p := NewPoint(55.7558, 37.6173)
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"lat":55.7558,"lon":37.6173}` {
+		t.Errorf("bad JSON: %s", data)
+	}
+	var p2 Point
+	if err := json.Unmarshal(data, &p2); err != nil || p2 != p {
+		t.Error("bad JSON round-trip:", p2, err)
+	}
+	if err := json.Unmarshal([]byte(`[37.6173, 55.7558]`), &p2); err != nil || p2 != p {
+		t.Error("bad GeoJSON position:", p2, err)
+	}
+	if err := json.Unmarshal([]byte(`{"Lat":55.7558,"Lon":37.6173}`), &p2); err != nil || p2 != p {
+		t.Error("bad JSON object:", p2, err)
+	}
+	for _, s := range []string{`{"lat":95,"lon":0}`, `{"lat":55}`, `[1]`, `"foo"`} {
+		if err := json.Unmarshal([]byte(s), &p2); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+	data, err = json.Marshal(NaNPoint)
+	if err != nil || string(data) != "null" {
+		t.Errorf("bad NaNPoint JSON: %s %v", data, err)
+	}
+	if err := json.Unmarshal(data, &p2); err != nil || !math.IsNaN(p2.Lat()) {
+		t.Error("bad NaNPoint round-trip:", p2, err)
+	}
+}
+
+func TestPointText(t *testing.T) {
+	p := NewPoint(-33.8568, 151.2153)
+	text, err := p.MarshalText()
+	if err != nil || string(text) != "-33.8568,151.2153" {
+		t.Errorf("bad text: %s %v", text, err)
+	}
+	var p2 Point
+	if err := p2.UnmarshalText(text); err != nil || p2 != p {
+		t.Error("bad text round-trip:", p2, err)
+	}
+	if err := p2.UnmarshalText([]byte(p.String())); err != nil || p2.Distance(p) > 0.001 {
+		t.Error("bad String round-trip:", p2, err)
+	}
+}
+
+func TestPointBinary(t *testing.T) {
+	p := NewPoint(55.7558, 37.6173)
+	data, err := p.MarshalBinary()
+	if err != nil || len(data) != 17 || data[0] != pointBinaryVersion {
+		t.Fatal("bad binary:", data, err)
+	}
+	var p2 Point
+	if err := p2.UnmarshalBinary(data); err != nil || p2 != p {
+		t.Error("bad binary round-trip:", p2, err)
+	}
+	// формат без версии
+	var p3 Point
+	if err := p3.UnmarshalBinary(data[1:]); err != nil || p3 != p {
+		t.Error("bad legacy binary:", p3, err)
+	}
+	data[0] = 2
+	if err := p3.UnmarshalBinary(data); err == nil {
+		t.Error("expected error for unknown version")
+	}
+	for _, bad := range []Point{{91, 0}, {0, -181}, {math.NaN(), 10}, {math.Inf(1), 0}} {
+		data, _ := bad.MarshalBinary()
+		if err := p3.UnmarshalBinary(data); err == nil {
+			t.Errorf("%v: expected error for bad coordinates", bad)
+		}
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode([]Point{p, NaNPoint}); err != nil {
+		t.Fatal(err)
+	}
+	var points []Point
+	if err := gob.NewDecoder(&buf).Decode(&points); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0] != p || !math.IsNaN(points[1].Lat()) {
+		t.Error("bad gob round-trip:", points)
+	}
+}
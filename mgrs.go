@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Буквы квадратов 100 км в системе MGRS для зон UTM.
+var (
+	mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"} // наборы для зон 1, 2, 3 (mod 3)
+	mgrsRows    = "ABCDEFGHJKLMNPQRSTUV"                        // для четных зон смещение на 5
+)
+
+// Буквы квадратов 100 км в системе MGRS для полярных зон UPS: A, B, Y, Z.
+var (
+	upsColumns     = map[byte]string{'A': "JKLPQRSTUXYZ", 'B': "ABCFGHJKLPQR", 'Y': "RSTUXYZ", 'Z': "ABCFGHJ"}
+	upsMinEasting  = map[byte]int{'A': 8, 'B': 20, 'Y': 13, 'Z': 20} // в сотнях километров
+	upsRowsSouth   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	upsRowsNorth   = "ABCDEFGHJKLMNP"
+	upsMinNorthing = map[bool]int{false: 8, true: 13} // в сотнях километров
+)
+
+// MGRS возвращает обозначение точки в системе MGRS (Military Grid Reference System) с
+// указанным количеством цифр для каждой координаты: от 0 (квадрат 100 км) до 5 (квадрат 1 м).
+// Координаты внутри квадрата отбрасываются, а не округляются, как того требует стандарт.
+func (p Point) MGRS(digits int) (string, error) {
+	if digits < 0 || digits > 5 {
+		return "", fmt.Errorf("bad MGRS precision: %d", digits)
+	}
+	u, err := p.UTM()
+	if err != nil {
+		return "", err
+	}
+	e100k := int(math.Floor(u.Easting / 100000))
+	n100k := int(math.Floor(u.Northing / 100000))
+	var prefix string
+	if u.UPS() {
+		cols, rows := upsColumns[u.Band], upsRowsSouth
+		if u.North() {
+			rows = upsRowsNorth
+		}
+		col, row := e100k-upsMinEasting[u.Band], n100k-upsMinNorthing[u.North()]
+		if col < 0 || col >= len(cols) || row < 0 || row >= len(rows) {
+			return "", ErrBadUTM
+		}
+		prefix = fmt.Sprintf("%c%c%c", u.Band, cols[col], rows[row])
+	} else {
+		cols := mgrsColumns[(u.Zone-1)%3]
+		if e100k < 1 || e100k > len(cols) {
+			return "", ErrBadUTM
+		}
+		row := n100k % 20
+		if u.Zone%2 == 0 {
+			row = (row + 5) % 20
+		}
+		prefix = fmt.Sprintf("%02d%c%c%c", u.Zone, u.Band, cols[e100k-1], mgrsRows[row])
+	}
+	// координаты внутри квадрата 100 км с нужной точностью
+	scale := math.Pow(10, float64(5-digits))
+	e := int(math.Floor(math.Mod(u.Easting, 100000) / scale))
+	n := int(math.Floor(math.Mod(u.Northing, 100000) / scale))
+	if digits == 0 {
+		return prefix, nil
+	}
+	return fmt.Sprintf("%s%0*d%0*d", prefix, digits, e, digits, n), nil
+}
+
+// ParseMGRS разбирает обозначение в системе MGRS и возвращает координаты UTM (или UPS)
+// юго-западного угла квадрата и размер его стороны в метрах. Пробелы в обозначении
+// игнорируются.
+func ParseMGRS(s string) (UTM, float64, error) {
+	ref := strings.ToUpper(strings.Join(strings.Fields(s), ""))
+	bad := fmt.Errorf("bad MGRS: %q", s)
+	// номер зоны: до двух цифр (отсутствует для полярных зон)
+	var zoneLen int
+	for zoneLen < len(ref) && zoneLen < 2 && ref[zoneLen] >= '0' && ref[zoneLen] <= '9' {
+		zoneLen++
+	}
+	if len(ref) < zoneLen+3 {
+		return UTM{}, 0, bad
+	}
+	var u UTM
+	if zoneLen > 0 {
+		u.Zone, _ = strconv.Atoi(ref[:zoneLen])
+	}
+	u.Band = ref[zoneLen]
+	col, row := ref[zoneLen+1], ref[zoneLen+2]
+	digits := ref[zoneLen+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return UTM{}, 0, bad
+	}
+	precision := len(digits) / 2
+	size := math.Pow(10, float64(5-precision)) // размер квадрата в метрах
+	var e, n float64
+	if precision > 0 {
+		ev, err1 := strconv.Atoi(digits[:precision])
+		nv, err2 := strconv.Atoi(digits[precision:])
+		if err1 != nil || err2 != nil {
+			return UTM{}, 0, bad
+		}
+		e, n = float64(ev)*size, float64(nv)*size
+	}
+	if u.UPS() {
+		if zoneLen > 0 {
+			return UTM{}, 0, bad
+		}
+		rows := upsRowsSouth
+		if u.North() {
+			rows = upsRowsNorth
+		}
+		ci := strings.IndexByte(upsColumns[u.Band], col)
+		ri := strings.IndexByte(rows, row)
+		if ci < 0 || ri < 0 {
+			return UTM{}, 0, bad
+		}
+		u.Easting = float64(ci+upsMinEasting[u.Band])*100000 + e
+		u.Northing = float64(ri+upsMinNorthing[u.North()])*100000 + n
+	} else {
+		if u.Zone < 1 || u.Zone > 60 || strings.IndexByte(utmBands, u.Band) < 0 {
+			return UTM{}, 0, bad
+		}
+		ci := strings.IndexByte(mgrsColumns[(u.Zone-1)%3], col)
+		ri := strings.IndexByte(mgrsRows, row)
+		if ci < 0 || ri < 0 {
+			return UTM{}, 0, bad
+		}
+		if u.Zone%2 == 0 {
+			ri = (ri + 15) % 20
+		}
+		u.Easting = float64(ci+1)*100000 + e
+		// северное смещение повторяется каждые 2000 км: выбираем вариант, попадающий в полосу
+		bandLat := float64(strings.IndexByte(utmBands, u.Band)*8 - 80)
+		bottom, err := Point{bandLat, float64(u.Zone-1)*6 - 180 + 3}.UTM()
+		if err != nil {
+			return UTM{}, 0, bad
+		}
+		minNorthing := math.Floor(bottom.Northing/100000)*100000 - 200000
+		u.Northing = float64(ri)*100000 + n
+		for u.Northing < minNorthing {
+			u.Northing += 2000000
+		}
+	}
+	if _, err := u.Point(); err != nil {
+		return UTM{}, 0, bad
+	}
+	return u, size, nil
+}
+
+// ParseMGRSPoint разбирает обозначение в системе MGRS и возвращает центр соответствующего
+// квадрата.
+func ParseMGRSPoint(s string) (Point, error) {
+	u, size, err := ParseMGRS(s)
+	if err != nil {
+		return NaNPoint, err
+	}
+	u.Easting += size / 2
+	u.Northing += size / 2
+	return u.Point()
+}
@@ -0,0 +1,86 @@
+package geo
+
+import "math"
+
+// Bearing возвращает начальный азимут (курс) из точки p на точку p2 в градусах [0, 360).
+// http://www.movable-type.co.uk/scripts/latlong.html
+func (p Point) Bearing(p2 Point) float64 {
+	return sphericalAzimuth(p, p2)
+}
+
+// FinalBearing возвращает конечный азимут при движении по большому кругу из точки p в точку p2
+// в градусах [0, 360).
+func (p Point) FinalBearing(p2 Point) float64 {
+	return normalizeAzimuth(sphericalAzimuth(p2, p) + 180)
+}
+
+// Destination возвращает точку, в которую можно попасть из текущей, пройдя указанное расстояние
+// в километрах по большому кругу с начальным азимутом bearing в градусах.
+func (p Point) Destination(bearing, distance float64) Point {
+	delta := distance / erath_radius // угловое расстояние
+	theta := bearing * (math.Pi / 180.0)
+	lat1 := p[0] * (math.Pi / 180.0)
+	lon1 := p[1] * (math.Pi / 180.0)
+	sinLat := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(sinLat)
+	y := math.Sin(theta) * math.Sin(delta) * math.Cos(lat1)
+	x := math.Cos(delta) - math.Sin(lat1)*sinLat
+	lon2 := lon1 + math.Atan2(y, x)
+	return Point{lat2 * (180.0 / math.Pi), normalizeLon(lon2 * (180.0 / math.Pi))}
+}
+
+// Midpoint возвращает точку, находящуюся посередине пути по большому кругу между двумя точками.
+func (p Point) Midpoint(p2 Point) Point {
+	return p.Intermediate(p2, 0.5)
+}
+
+// Intermediate возвращает точку, находящуюся на указанной доле fraction пути по большому кругу
+// из точки p в точку p2: 0 соответствует начальной точке, 1 - конечной.
+//
+// Для антиподальных (и почти антиподальных) точек большой круг не определен: через них проходит
+// любой меридиан. В этом случае возвращаются сами точки для fraction 0 и 1, а для остальных
+// значений - точка на пути через северный полюс вдоль меридиана начальной точки (через южный,
+// если начальная точка - северный полюс).
+func (p Point) Intermediate(p2 Point, fraction float64) Point {
+	switch fraction {
+	case 0:
+		return p
+	case 1:
+		return p2
+	}
+	lat1 := p[0] * (math.Pi / 180.0)
+	lon1 := p[1] * (math.Pi / 180.0)
+	lat2 := p2[0] * (math.Pi / 180.0)
+	lon2 := p2[1] * (math.Pi / 180.0)
+	delta := p.Distance(p2) / erath_radius // угловое расстояние
+	if delta == 0 {
+		return p
+	}
+	if math.Sin(delta) < 1e-9 && delta > math.Pi/2 { // точки антиподальные
+		bearing := 0.0
+		if p[0] >= 90 {
+			bearing = 180
+		}
+		return p.Destination(bearing, fraction*math.Pi*erath_radius)
+	}
+	a := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	b := math.Sin(fraction*delta) / math.Sin(delta)
+	x := a*math.Cos(lat1)*math.Cos(lon1) + b*math.Cos(lat2)*math.Cos(lon2)
+	y := a*math.Cos(lat1)*math.Sin(lon1) + b*math.Cos(lat2)*math.Sin(lon2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+	lat := math.Atan2(z, math.Sqrt(x*x+y*y))
+	lon := math.Atan2(y, x)
+	return Point{lat * (180.0 / math.Pi), lon * (180.0 / math.Pi)}
+}
+
+// normalizeLon приводит долготу в градусах к диапазону [-180, 180].
+func normalizeLon(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
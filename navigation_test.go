@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNavigation(t *testing.T) {
+	// пример с http://www.movable-type.co.uk/scripts/latlong.html
+	p1 := NewPoint(dms(50, 3, 59), dms(-5, 42, 53))
+	p2 := NewPoint(dms(58, 38, 38), dms(-3, 4, 12))
+	if b := p1.Bearing(p2); math.Abs(b-dms(9, 7, 11)) > 1e-3 {
+		t.Errorf("bad bearing: %f", b)
+	}
+	if b := p1.FinalBearing(p2); math.Abs(b-dms(11, 16, 31)) > 1e-3 {
+		t.Errorf("bad final bearing: %f", b)
+	}
+	mid := p1.Midpoint(p2)
+	if math.Abs(mid.Lat()-dms(54, 21, 44)) > 1e-3 || math.Abs(mid.Lon()-dms(-4, 31, 50)) > 1e-3 {
+		t.Errorf("bad midpoint: %v", mid)
+	}
+	if d1, d2 := p1.Distance(mid), mid.Distance(p2); math.Abs(d1-d2) > 1e-6 {
+		t.Errorf("midpoint is not in the middle: %f %f", d1, d2)
+	}
+	if p := p1.Intermediate(p2, 1); p.Distance(p2) > 1e-6 {
+		t.Errorf("bad intermediate point: %v", p)
+	}
+	dest := p1.Destination(p1.Bearing(p2), p1.Distance(p2))
+	if d := dest.Distance(p2); d > 1e-6 {
+		t.Errorf("bad destination: %v (%f km)", dest, d)
+	}
+	// пересечение линии перемены дат
+	dest = NewPoint(65, 179.5).Destination(90, 100)
+	if dest.Lon() > 0 || dest.Lon() < -180 {
+		t.Errorf("bad longitude after antimeridian: %v", dest)
+	}
+}
+
+func TestIntermediateAntipodal(t *testing.T) {
+	p1, p2 := NewPoint(30, 40), NewPoint(-30, -140)
+	if p := p1.Intermediate(p2, 0); p != p1 {
+		t.Errorf("bad start point: %v", p)
+	}
+	if p := p1.Intermediate(p2, 1); p != p2 {
+		t.Errorf("bad end point: %v", p)
+	}
+	for _, fraction := range []float64{0.25, 0.5, 0.75} {
+		p := p1.Intermediate(p2, fraction)
+		if math.IsNaN(p.Lat()) || math.IsNaN(p.Lon()) {
+			t.Fatalf("NaN point for fraction %v", fraction)
+		}
+		// точка лежит на большом круге: сумма расстояний до концов равна половине окружности
+		if d := p.Distance(p1) + p.Distance(p2); math.Abs(d-math.Pi*erath_radius) > 1e-6 {
+			t.Errorf("%v: point %v is not on a great circle: %f", fraction, p, d)
+		}
+		if d := p.Distance(p1) / (math.Pi * erath_radius); math.Abs(d-fraction) > 1e-9 {
+			t.Errorf("%v: bad fraction: %f", fraction, d)
+		}
+	}
+	if p := NewPoint(90, 0).Midpoint(NewPoint(-90, 0)); math.Abs(p.Lat()) > 1e-9 {
+		t.Errorf("bad midpoint between poles: %v", p)
+	}
+}
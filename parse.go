@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Ошибки, возвращаемые при создании и разборе координат точки.
+var (
+	ErrBadLatitude  = errors.New("bad latitude")
+	ErrBadLongitude = errors.New("bad longitude")
+)
+
+// MakePoint возвращает описание точки с указанными координатами или ошибку, если координаты
+// выходят за допустимые пределы. В отличие от NewPoint, никогда не вызывает panic, поэтому
+// подходит для данных, полученных от внешних источников.
+func MakePoint(lat, lon float64) (Point, error) {
+	if !(lat >= -90 && lat <= 90) {
+		return NaNPoint, ErrBadLatitude
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return NaNPoint, ErrBadLongitude
+	}
+	return Point{lat, lon}, nil
+}
+
+// nmeaRe описывает координаты в формате NMEA: ddmm.mmmm,N,dddmm.mmmm,E
+var nmeaRe = regexp.MustCompile(
+	`^(\d{2})(\d{2}(?:\.\d+)?)\s*,?\s*([NS])\s*,?\s*(\d{3})(\d{2}(?:\.\d+)?)\s*,?\s*([EW])$`)
+
+// ParsePoint разбирает строку с координатами точки и возвращает ее описание. Поддерживаются
+// следующие форматы:
+//
+//	[55.755800,37.617300]          - формат, возвращаемый Point.String
+//	55.7558, 37.6173               - десятичные градусы (широта, долгота)
+//	55.7558N 37.6173E              - десятичные градусы с указанием полушария
+//	55°45'20.9"N 37°37'02.3"E      - градусы, минуты и секунды
+//	5545.3482,N,03737.0380,E       - формат NMEA (ddmm.mmmm и dddmm.mmmm)
+//
+// Если полушарие указано буквами N/S/E/W, то порядок широты и долготы может быть любым.
+func ParsePoint(s string) (Point, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = s[1 : len(s)-1]
+	}
+	if m := nmeaRe.FindStringSubmatch(s); m != nil {
+		lat := nmeaCoord(m[1], m[2], m[3] == "S")
+		lon := nmeaCoord(m[4], m[5], m[6] == "W")
+		return MakePoint(lat, lon)
+	}
+	first, second, ok := splitPoint(s)
+	if !ok {
+		return NaNPoint, fmt.Errorf("bad point: %q", s)
+	}
+	v1, h1, err := parseCoord(first)
+	if err != nil {
+		return NaNPoint, err
+	}
+	v2, h2, err := parseCoord(second)
+	if err != nil {
+		return NaNPoint, err
+	}
+	// если полушария указаны, то по ним определяем, где широта, а где долгота
+	if h1 == 'E' || h1 == 'W' || h2 == 'N' || h2 == 'S' {
+		v1, v2, h1, h2 = v2, v1, h2, h1
+	}
+	if h1 == 'E' || h1 == 'W' || h2 == 'N' || h2 == 'S' {
+		return NaNPoint, fmt.Errorf("bad point hemispheres: %q", s)
+	}
+	return MakePoint(v1, v2)
+}
+
+// nmeaCoord возвращает координату в градусах по градусам и минутам в формате NMEA.
+func nmeaCoord(deg, minutes string, negative bool) float64 {
+	d, _ := strconv.ParseFloat(deg, 64)
+	m, _ := strconv.ParseFloat(minutes, 64)
+	v := d + m/60
+	if negative {
+		v = -v
+	}
+	return v
+}
+
+// splitPoint разделяет строку с координатами на две части: широту и долготу.
+func splitPoint(s string) (string, string, bool) {
+	// разделение по букве полушария
+	if i := strings.IndexAny(s, "NSEW"); i >= 0 {
+		if i == 0 { // полушарие указано перед значением: N55 45 E37 37
+			if j := strings.IndexAny(s[1:], "NSEW"); j >= 0 {
+				return s[:j+1], s[j+1:], true
+			}
+			return "", "", false
+		}
+		return s[:i+1], s[i+1:], true
+	}
+	// разделение по запятой или точке с запятой
+	if parts := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }); len(parts) == 2 {
+		return parts[0], parts[1], true
+	}
+	// разделение по пробелам: половина значений на широту, половина на долготу
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields)%2 != 0 || len(fields) > 6 {
+		return "", "", false
+	}
+	n := len(fields) / 2
+	return strings.Join(fields[:n], " "), strings.Join(fields[n:], " "), true
+}
+
+// parseCoord разбирает одну координату в десятичных градусах или в формате градусы, минуты,
+// секунды. Возвращает значение и букву полушария, если она была указана.
+func parseCoord(s string) (float64, byte, error) {
+	s = strings.Trim(s, " \t,;")
+	var hemi byte
+	if s != "" && strings.IndexByte("NSEW", s[0]) >= 0 {
+		hemi, s = s[0], s[1:]
+	} else if n := len(s); n > 0 && strings.IndexByte("NSEW", s[n-1]) >= 0 {
+		hemi, s = s[n-1], s[:n-1]
+	}
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	if negative || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	// все, кроме цифр и десятичной точки, считаем разделителями градусов, минут и секунд
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if len(fields) == 0 || len(fields) > 3 {
+		return 0, 0, fmt.Errorf("bad coordinate: %q", s)
+	}
+	var value float64
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("bad coordinate: %q", s)
+		}
+		if i > 0 && v >= 60 {
+			return 0, 0, fmt.Errorf("bad minutes or seconds: %q", s)
+		}
+		switch i {
+		case 0:
+			value = v
+		case 1:
+			value += v / 60
+		case 2:
+			value += v / 3600
+		}
+	}
+	if negative || hemi == 'S' || hemi == 'W' {
+		value = -value
+	}
+	return value, hemi, nil
+}
@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMakePoint(t *testing.T) {
+	if _, err := MakePoint(91, 0); err != ErrBadLatitude {
+		t.Error("expected bad latitude:", err)
+	}
+	if _, err := MakePoint(0, -181); err != ErrBadLongitude {
+		t.Error("expected bad longitude:", err)
+	}
+	if _, err := MakePoint(math.NaN(), 0); err != ErrBadLatitude {
+		t.Error("expected bad latitude for NaN:", err)
+	}
+	if p, err := MakePoint(55.75, 37.61); err != nil || p != NewPoint(55.75, 37.61) {
+		t.Error("bad point:", p, err)
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	moscow := NewPoint(55.7558, 37.6173)
+	for _, s := range []string{
+		moscow.String(),
+		"55.7558, 37.6173",
+		"55.7558 37.6173",
+		"55.7558N 37.6173E",
+		"37.6173E, 55.7558N",
+		"N55.7558 E37.6173",
+		`55°45'20.88"N 37°37'02.28"E`,
+		"55 45 20.88 37 37 2.28",
+		"5545.3480,N,03737.0380,E",
+		"5545.3480N 03737.0380E",
+	} {
+		p, err := ParsePoint(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if d := p.Distance(moscow); d > 0.001 {
+			t.Errorf("%q: %v (%f km)", s, p, d)
+		}
+	}
+	southWest := NewPoint(-33.8568, -151.2153)
+	for _, s := range []string{
+		"-33.8568, -151.2153",
+		"33.8568S 151.2153W",
+		`33°51'24.48"S 151°12'55.08"W`,
+		"3351.4080,S,15112.9180,W",
+	} {
+		p, err := ParsePoint(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		if d := p.Distance(southWest); d > 0.001 {
+			t.Errorf("%q: %v (%f km)", s, p, d)
+		}
+	}
+	for _, s := range []string{
+		"",
+		"55.7558",
+		"foo, bar",
+		"95.0, 37.0",
+		"55.0N 37.0N",
+		"55 75 20 37 37 2",
+		"[55.7558;37.6173;1]",
+	} {
+		if p, err := ParsePoint(s); err == nil {
+			t.Errorf("%q: expected error, got %v", s, p)
+		}
+	}
+}
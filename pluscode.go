@@ -0,0 +1,214 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Параметры кодирования Open Location Code (Plus Codes).
+// https://github.com/google/open-location-code/blob/main/docs/specification.md
+const (
+	plusAlphabet     = "23456789CFGHJMPQRVWX"
+	plusSeparator    = '+'
+	plusSeparatorPos = 8           // позиция разделителя в полном коде
+	plusPadding      = '0'         // символ заполнения для кодов короче 8 символов
+	plusPairLength   = 10          // длина части кода, состоящей из пар широта-долгота
+	plusMaxLength    = 15          // максимальная длина кода без разделителя
+	plusGridRows     = 5           // количество строк в сетке уточняющих символов
+	plusGridColumns  = 4           // количество колонок в сетке уточняющих символов
+	plusLatPrecision = 8000 * 3125 // единиц на градус широты: 8000 * 5^5
+	plusLonPrecision = 8000 * 1024 // единиц на градус долготы: 8000 * 4^5
+)
+
+// ErrBadPlusCode возвращается при некорректном коде Open Location Code.
+var ErrBadPlusCode = errors.New("bad plus code")
+
+// PlusCode возвращает полный код Open Location Code (Plus Code) для точки указанной длины
+// без учета разделителя: 2, 4, 6, 8 или от 10 до 15 символов. Длина 10 соответствует области
+// около 14x14 м и используется по умолчанию в Google Maps.
+func (p Point) PlusCode(length int) (string, error) {
+	if length < 2 || (length < plusPairLength && length%2 == 1) || length > plusMaxLength {
+		return "", fmt.Errorf("bad plus code length: %d", length)
+	}
+	if p.isNaN() {
+		return "", ErrBadLatitude
+	}
+	// переводим координаты в целые числа в единицах наибольшей точности
+	lat := int64(math.Floor((p.Lat() + 90) * plusLatPrecision))
+	if lat < 0 {
+		lat = 0
+	} else if lat >= 180*plusLatPrecision {
+		lat = 180*plusLatPrecision - 1 // северный полюс относится к самой северной ячейке
+	}
+	lon := int64(math.Floor((normalizeLon(p.Lon()) + 180) * plusLonPrecision))
+	if lon >= 360*plusLonPrecision {
+		lon = 0
+	}
+	code := make([]byte, plusMaxLength)
+	// символы сетки кодируются с конца
+	for i := plusMaxLength - 1; i >= plusPairLength; i-- {
+		code[i] = plusAlphabet[(lat%plusGridRows)*plusGridColumns+lon%plusGridColumns]
+		lat /= plusGridRows
+		lon /= plusGridColumns
+	}
+	for i := plusPairLength - 2; i >= 0; i -= 2 {
+		code[i] = plusAlphabet[lat%20]
+		code[i+1] = plusAlphabet[lon%20]
+		lat /= 20
+		lon /= 20
+	}
+	code = code[:length]
+	for len(code) < plusSeparatorPos {
+		code = append(code, plusPadding)
+	}
+	// вставляем разделитель
+	code = append(code[:plusSeparatorPos], append([]byte{plusSeparator}, code[plusSeparatorPos:]...)...)
+	return string(code), nil
+}
+
+// plusCodeCheck проверяет синтаксис кода и возвращает его в верхнем регистре и признак того,
+// что код является полным.
+func plusCodeCheck(code string) (string, bool, error) {
+	code = strings.ToUpper(code)
+	sep := strings.IndexByte(code, plusSeparator)
+	if sep < 0 || sep != strings.LastIndexByte(code, plusSeparator) ||
+		sep > plusSeparatorPos || sep%2 == 1 || len(code)-sep-1 == 1 ||
+		len(code)-1 > plusMaxLength {
+		return "", false, ErrBadPlusCode
+	}
+	// символы заполнения допустимы только в полном коде, парами и непосредственно перед
+	// разделителем, после которого в этом случае ничего не должно быть
+	if pad := strings.IndexByte(code, plusPadding); pad >= 0 {
+		if sep < plusSeparatorPos || pad == 0 || pad%2 == 1 || sep != len(code)-1 ||
+			strings.Trim(code[pad:sep], string(plusPadding)) != "" {
+			return "", false, ErrBadPlusCode
+		}
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] != plusSeparator && code[i] != plusPadding && strings.IndexByte(plusAlphabet, code[i]) < 0 {
+			return "", false, ErrBadPlusCode
+		}
+	}
+	full := sep == plusSeparatorPos
+	if full && (strings.IndexByte(plusAlphabet, code[0]) >= 9 || strings.IndexByte(plusAlphabet, code[1]) >= 18) {
+		return "", false, ErrBadPlusCode // широта больше 90° или долгота больше 180°
+	}
+	return code, full, nil
+}
+
+// PlusCodeBBox возвращает область, описываемую полным кодом Open Location Code.
+func PlusCodeBBox(code string) (BBox, error) {
+	code, full, err := plusCodeCheck(code)
+	if err != nil {
+		return EmptyBBox, err
+	}
+	if !full {
+		return EmptyBBox, fmt.Errorf("not a full plus code: %q", code)
+	}
+	// отбрасываем разделитель и символы заполнения
+	code = strings.TrimRight(code[:plusSeparatorPos], string(plusPadding)) + code[plusSeparatorPos+1:]
+	// вес текущего символа в единицах наибольшей точности
+	latPlace, lonPlace := int64(20*plusLatPrecision), int64(20*plusLonPrecision)
+	var lat, lon int64
+	for i := 0; i < len(code) && i < plusPairLength; i += 2 {
+		if i > 0 {
+			latPlace /= 20
+			lonPlace /= 20
+		}
+		lat += int64(strings.IndexByte(plusAlphabet, code[i])) * latPlace
+		lon += int64(strings.IndexByte(plusAlphabet, code[i+1])) * lonPlace
+	}
+	for i := plusPairLength; i < len(code); i++ {
+		latPlace /= plusGridRows
+		lonPlace /= plusGridColumns
+		v := int64(strings.IndexByte(plusAlphabet, code[i]))
+		lat += v / plusGridColumns * latPlace
+		lon += v % plusGridColumns * lonPlace
+	}
+	south := float64(lat)/plusLatPrecision - 90
+	west := float64(lon)/plusLonPrecision - 180
+	return BBox{
+		South: south,
+		West:  west,
+		North: math.Min(90, south+float64(latPlace)/plusLatPrecision),
+		East:  west + float64(lonPlace)/plusLonPrecision,
+	}, nil
+}
+
+// DecodePlusCode возвращает центр области, описываемой полным кодом Open Location Code.
+func DecodePlusCode(code string) (Point, error) {
+	b, err := PlusCodeBBox(code)
+	if err != nil {
+		return NaNPoint, err
+	}
+	return Point{(b.South + b.North) / 2, math.Min(180, (b.West+b.East)/2)}, nil
+}
+
+// plusResolutions описывает размер ячейки в градусах для каждой пары символов кода.
+var plusResolutions = [...]float64{20, 1, 0.05, 0.0025, 0.000125}
+
+// ShortenPlusCode возвращает сокращенный код, из которого удалены начальные символы, которые
+// можно восстановить по близкой опорной точке (например, центру города). Коды с заполнением
+// и другие коды без символов после разделителя не сокращаются. Если опорная точка слишком
+// далеко, возвращается исходный код.
+func ShortenPlusCode(code string, ref Point) (string, error) {
+	code, full, err := plusCodeCheck(code)
+	if err != nil {
+		return "", err
+	}
+	if !full {
+		return "", fmt.Errorf("not a full plus code: %q", code)
+	}
+	if strings.HasSuffix(code, string(plusSeparator)) {
+		return "", fmt.Errorf("can't shorten plus code: %q", code)
+	}
+	center, _ := DecodePlusCode(code)
+	lat := math.Max(-90, math.Min(90, ref.Lat()))
+	distance := math.Max(math.Abs(center.Lat()-lat), math.Abs(normalizeLon(center.Lon()-ref.Lon())))
+	// оставляем запас, чтобы код можно было восстановить при небольшом смещении опорной точки
+	for i := len(plusResolutions) - 2; i >= 1; i-- {
+		if distance < plusResolutions[i]*0.3 {
+			return code[(i+1)*2:], nil
+		}
+	}
+	return code, nil
+}
+
+// RecoverPlusCode восстанавливает полный код из сокращенного по опорной точке: выбирается
+// ближайшая к опорной точке область с такими же конечными символами. Полный код возвращается
+// без изменений (в верхнем регистре).
+func RecoverPlusCode(code string, ref Point) (string, error) {
+	code, full, err := plusCodeCheck(code)
+	if err != nil {
+		return "", err
+	}
+	if full {
+		return code, nil
+	}
+	ref = Point{math.Max(-90, math.Min(90, ref.Lat())), normalizeLon(ref.Lon())}
+	missing := plusSeparatorPos - strings.IndexByte(code, plusSeparator)
+	resolution := math.Pow(20, 2-float64(missing/2)) // размер области, задаваемой опущенными символами
+	prefix, err := ref.PlusCode(plusPairLength)
+	if err != nil {
+		return "", err
+	}
+	center, err := DecodePlusCode(prefix[:missing] + code)
+	if err != nil {
+		return "", err
+	}
+	// сдвигаем найденную область на ее размер, если опорная точка ближе к соседней
+	lat, lon := center.Lat(), center.Lon()
+	if d := lat - ref.Lat(); d > resolution/2 && lat-resolution >= -90 {
+		lat -= resolution
+	} else if d < -resolution/2 && lat+resolution <= 90 {
+		lat += resolution
+	}
+	if d := lon - ref.Lon(); d > resolution/2 {
+		lon -= resolution
+	} else if d < -resolution/2 {
+		lon += resolution
+	}
+	return Point{lat, normalizeLon(lon)}.PlusCode(missing + len(code) - 1)
+}
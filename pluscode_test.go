@@ -0,0 +1,127 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPlusCode(t *testing.T) {
+	// примеры из тестовых данных эталонной реализации
+	for _, test := range []struct {
+		lat, lon float64
+		length   int
+		code     string
+	}{
+		{20.375, 2.775, 6, "7FG49Q00+"},
+		{20.3700625, 2.7821875, 10, "7FG49QCJ+2V"},
+		{20.3701125, 2.782234375, 11, "7FG49QCJ+2VX"},
+		{20.3701135, 2.78223535156, 13, "7FG49QCJ+2VXGJ"},
+		{47.0000625, 8.0000625, 10, "8FVC2222+22"},
+		{-41.2730625, 174.7859375, 10, "4VCPPQGP+Q9"},
+		{0.5, -179.5, 4, "62G20000+"},
+		{-89.5, -179.5, 4, "22220000+"},
+		{20.5, 2.5, 4, "7FG40000+"},
+		{-89.9999375, -179.9999375, 10, "22222222+22"},
+		{0.5, 179.5, 4, "6VGX0000+"},
+		{1, 1, 11, "6FH32222+222"},
+		{90, 1, 4, "CFX30000+"},
+		{1, 180, 4, "62H20000+"},
+		{90, 1, 10, "CFX3X2X2+X2"},
+	} {
+		code, err := Point{test.lat, test.lon}.PlusCode(test.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Errorf("%v %v: %s, want %s", test.lat, test.lon, code, test.code)
+		}
+		b, err := PlusCodeBBox(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := (Point{test.lat, test.lon}); p.Lat() < 90 && test.lon < 180 && !b.Contains(p) {
+			t.Errorf("%s: %v does not contain %v", code, b, p)
+		}
+	}
+	b, err := PlusCodeBBox("7fg49qcj+2v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(b.South-20.37) > 1e-9 || math.Abs(b.North-20.370125) > 1e-9 ||
+		math.Abs(b.West-2.782125) > 1e-9 || math.Abs(b.East-2.78225) > 1e-9 {
+		t.Errorf("bad bbox: %v", b)
+	}
+	for _, code := range []string{
+		"", "+", "8FVC2222", "8FVC2222+2", "8FVC2222++22", "8FV+", "8FVC22221+22",
+		"8FVC0000+22", "8F0C0000+", "8FVC2220+", "WFVC2222+22", "8XVC2222+22", "8FVC2222+2B",
+		"2222+22", // короткий код не декодируется
+	} {
+		if _, err := PlusCodeBBox(code); err == nil {
+			t.Errorf("%q: expected error", code)
+		}
+	}
+	for _, length := range []int{0, 1, 3, 9, 16} {
+		if _, err := (Point{1, 1}).PlusCode(length); err == nil {
+			t.Errorf("length %d: expected error", length)
+		}
+	}
+}
+
+func TestPlusCodeShort(t *testing.T) {
+	for _, test := range []struct {
+		code     string
+		lat, lon float64
+		short    string
+	}{
+		{"9C3W9QCJ+2VX", 51.3701125, -1.217765625, "+2VX"},
+		{"9C3W9QCJ+2VX", 51.3708675, -1.217765625, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", 51.3693575, -1.217765625, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", 51.3701125, -1.2270, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", 51.3701125, -1.45, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", 40, 10, "9C3W9QCJ+2VX"},
+	} {
+		short, err := ShortenPlusCode(test.code, Point{test.lat, test.lon})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if short != test.short {
+			t.Errorf("%s near %v,%v: %s, want %s", test.code, test.lat, test.lon, short, test.short)
+		}
+		full, err := RecoverPlusCode(short, Point{test.lat, test.lon})
+		if err != nil || full != test.code {
+			t.Errorf("%s: recovered %s %v", short, full, err)
+		}
+	}
+	// восстановление через границу ячейки, линию перемены дат и у полюса
+	for _, test := range []struct {
+		short    string
+		lat, lon float64
+		full     string
+	}{
+		{"9QCJ+2VX", 51.3, -1.3, "9C3W9QCJ+2VX"},
+		{"2222+22", 0.5, 179.9, "62G22222+22"}, // ближе ячейка к востоку от линии перемены дат
+		{"X2X2+X2", 89.6, 1, "CFX3X2X2+X2"},    // ячейка севернее полюса невозможна
+	} {
+		full, err := RecoverPlusCode(test.short, Point{test.lat, test.lon})
+		if err != nil || full != test.full {
+			t.Errorf("%s near %v,%v: %s %v, want %s", test.short, test.lat, test.lon, full, err, test.full)
+		}
+	}
+	if _, err := ShortenPlusCode("8FVC0000+", Point{47, 8}); err == nil {
+		t.Error("expected error for padded code")
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{r.Float64()*170 - 85, r.Float64()*360 - 180}
+		code, _ := p.PlusCode(10)
+		ref := Point{p.Lat() + r.Float64()*0.02 - 0.01, p.Lon() + r.Float64()*0.02 - 0.01}
+		short, err := ShortenPlusCode(code, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if full, err := RecoverPlusCode(short, ref); err != nil || full != code {
+			t.Fatalf("%s -> %s -> %s %v", code, short, full, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Point3D описывает координаты точки с высотой (lat, lon, height). Высота задается в метрах
+// над эллипсоидом WGS84.
+type Point3D [3]float64
+
+// NewPoint3D возвращает описание точки с указанными координатами и высотой в метрах. Как и
+// NewPoint, вызывает panic, если координаты выходят за допустимые пределы.
+func NewPoint3D(lat, lon, height float64) Point3D {
+	p := NewPoint(lat, lon)
+	return Point3D{p[0], p[1], height}
+}
+
+// MakePoint3D возвращает описание точки с указанными координатами и высотой в метрах или
+// ошибку, как в MakePoint, если координаты выходят за допустимые пределы.
+func MakePoint3D(lat, lon, height float64) (Point3D, error) {
+	p, err := MakePoint(lat, lon)
+	if err != nil {
+		return Point3D{math.NaN(), math.NaN(), math.NaN()}, err
+	}
+	return Point3D{p[0], p[1], height}, nil
+}
+
+// Lat возвращает широту.
+func (p Point3D) Lat() float64 {
+	return p[0]
+}
+
+// Lon возвращает долготу.
+func (p Point3D) Lon() float64 {
+	return p[1]
+}
+
+// Height возвращает высоту над эллипсоидом в метрах.
+func (p Point3D) Height() float64 {
+	return p[2]
+}
+
+// Point возвращает координаты точки без учета высоты.
+func (p Point3D) Point() Point {
+	return Point{p[0], p[1]}
+}
+
+// String возвращает строковое представление точки.
+func (p Point3D) String() string {
+	return fmt.Sprintf("[%f,%f,%.3f]", p[0], p[1], p[2])
+}
+
+// ECEF описывает геоцентрические координаты (Earth-Centered, Earth-Fixed) в метрах на эллипсоиде
+// WGS84: ось X направлена на пересечение экватора с нулевым меридианом, ось Z - на северный
+// полюс.
+type ECEF struct {
+	X, Y, Z float64
+}
+
+// ECEF возвращает геоцентрические координаты точки.
+func (p Point3D) ECEF() ECEF {
+	x, y, z := WGS84.toECEF(p.Point(), p.Height())
+	return ECEF{x, y, z}
+}
+
+// Point3D возвращает точку с высотой, соответствующую геоцентрическим координатам.
+func (c ECEF) Point3D() Point3D {
+	p, h := WGS84.fromECEF(c.X, c.Y, c.Z)
+	return Point3D{p[0], p[1], h}
+}
+
+// Distance возвращает расстояние по прямой между двумя точками в метрах.
+func (c ECEF) Distance(c2 ECEF) float64 {
+	return math.Sqrt((c2.X-c.X)*(c2.X-c.X) + (c2.Y-c.Y)*(c2.Y-c.Y) + (c2.Z-c.Z)*(c2.Z-c.Z))
+}
+
+// ENU описывает координаты в метрах в локальной системе East-North-Up с началом в опорной
+// точке: восток, север и вверх по нормали к эллипсоиду.
+type ENU struct {
+	E, N, U float64
+}
+
+// NED описывает координаты в метрах в локальной системе North-East-Down с началом в опорной
+// точке: север, восток и вниз по нормали к эллипсоиду.
+type NED struct {
+	N, E, D float64
+}
+
+// enuBasis возвращает синусы и косинусы широты и долготы опорной точки, задающие поворот
+// геоцентрической системы в локальную.
+func enuBasis(origin Point3D) (sinPhi, cosPhi, sinL, cosL float64) {
+	sinPhi, cosPhi = math.Sincos(origin.Lat() * (math.Pi / 180.0))
+	sinL, cosL = math.Sincos(origin.Lon() * (math.Pi / 180.0))
+	return
+}
+
+// ENU возвращает координаты точки в локальной системе East-North-Up с началом в origin.
+func (p Point3D) ENU(origin Point3D) ENU {
+	c, o := p.ECEF(), origin.ECEF()
+	dx, dy, dz := c.X-o.X, c.Y-o.Y, c.Z-o.Z
+	sinPhi, cosPhi, sinL, cosL := enuBasis(origin)
+	return ENU{
+		E: -sinL*dx + cosL*dy,
+		N: -sinPhi*cosL*dx - sinPhi*sinL*dy + cosPhi*dz,
+		U: cosPhi*cosL*dx + cosPhi*sinL*dy + sinPhi*dz,
+	}
+}
+
+// Point3D возвращает точку, заданную координатами в локальной системе с началом в origin.
+func (v ENU) Point3D(origin Point3D) Point3D {
+	o := origin.ECEF()
+	sinPhi, cosPhi, sinL, cosL := enuBasis(origin)
+	return ECEF{
+		X: o.X - sinL*v.E - sinPhi*cosL*v.N + cosPhi*cosL*v.U,
+		Y: o.Y + cosL*v.E - sinPhi*sinL*v.N + cosPhi*sinL*v.U,
+		Z: o.Z + cosPhi*v.N + sinPhi*v.U,
+	}.Point3D()
+}
+
+// NED возвращает координаты в системе North-East-Down.
+func (v ENU) NED() NED {
+	return NED{N: v.N, E: v.E, D: -v.U}
+}
+
+// Range возвращает расстояние до начала локальной системы в метрах.
+func (v ENU) Range() float64 {
+	return math.Sqrt(v.E*v.E + v.N*v.N + v.U*v.U)
+}
+
+// NED возвращает координаты точки в локальной системе North-East-Down с началом в origin.
+func (p Point3D) NED(origin Point3D) NED {
+	return p.ENU(origin).NED()
+}
+
+// ENU возвращает координаты в системе East-North-Up.
+func (v NED) ENU() ENU {
+	return ENU{E: v.E, N: v.N, U: -v.D}
+}
+
+// Point3D возвращает точку, заданную координатами в локальной системе с началом в origin.
+func (v NED) Point3D(origin Point3D) Point3D {
+	return v.ENU().Point3D(origin)
+}
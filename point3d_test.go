@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPoint3DECEF(t *testing.T) {
+	for _, test := range []struct {
+		point Point3D
+		ecef  ECEF
+	}{
+		{Point3D{0, 0, 0}, ECEF{6378137, 0, 0}},
+		{Point3D{0, 90, 100}, ECEF{0, 6378237, 0}},
+		{Point3D{90, 0, 0}, ECEF{0, 0, 6356752.314245}},
+		{Point3D{-90, 0, -10}, ECEF{0, 0, -6356742.314245}},
+	} {
+		c := test.point.ECEF()
+		if c.Distance(test.ecef) > 1e-6 {
+			t.Errorf("%v: %v, want %v", test.point, c, test.ecef)
+		}
+		p := c.Point3D()
+		if p.Point().Distance(test.point.Point()) > 1e-9 || math.Abs(p.Height()-test.point.Height()) > 1e-6 {
+			t.Errorf("%v: bad inverse %v", test.ecef, p)
+		}
+	}
+}
+
+func TestENU(t *testing.T) {
+	origin := NewPoint3D(55.7539, 37.6208, 150)
+	if p, err := MakePoint3D(55.7539, 37.6208, 150); err != nil || p != origin {
+		t.Errorf("bad point: %v %v", p, err)
+	}
+	if _, err := MakePoint3D(91, 0, 0); err != ErrBadLatitude {
+		t.Errorf("expected latitude error, got %v", err)
+	}
+	if p, err := MakePoint3D(0, 181, 0); err != ErrBadLongitude || !math.IsNaN(p.Lat()) {
+		t.Errorf("expected longitude error, got %v %v", p, err)
+	}
+	// точка на 100 м выше лежит на оси Up
+	v := Point3D{origin[0], origin[1], 250}.ENU(origin)
+	if math.Abs(v.E) > 1e-6 || math.Abs(v.N) > 1e-6 || math.Abs(v.U-100) > 1e-6 {
+		t.Errorf("bad up: %+v", v)
+	}
+	// точка в 1 км к северу на той же высоте: вертикаль отклоняется из-за кривизны Земли
+	north := origin.Point().Destination(0, 1)
+	v = Point3D{north[0], north[1], 150}.ENU(origin)
+	if math.Abs(v.E) > 1e-6 || math.Abs(v.N-1000) > 5 || v.U > 0 || v.U < -0.1 {
+		t.Errorf("bad north: %+v", v)
+	}
+	if ned := v.NED(); ned.N != v.N || ned.E != v.E || ned.D != -v.U {
+		t.Errorf("bad NED: %+v", ned)
+	}
+	if math.Abs(v.Range()-Point3D{north[0], north[1], 150}.ECEF().Distance(origin.ECEF())) > 1e-6 {
+		t.Errorf("bad range: %f", v.Range())
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		o := Point3D{r.Float64()*180 - 90, r.Float64()*360 - 180, r.Float64() * 1000}
+		p := Point3D{o[0] + r.Float64() - 0.5, normalizeLon(o[1] + r.Float64() - 0.5), r.Float64() * 10000}
+		p1 := p.ENU(o).Point3D(o)
+		p2 := p.NED(o).Point3D(o)
+		for _, q := range []Point3D{p1, p2} {
+			if q.ECEF().Distance(p.ECEF()) > 1e-6 {
+				t.Fatalf("%v from %v: %v", p, o, q)
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package geo
+
+import "math"
+
+// Ring описывает замкнутый контур из точек. Последняя точка может совпадать с первой, но это
+// не обязательно: контур замыкается автоматически. Ребра контура - дуги большого круга, поэтому
+// все вычисления корректны и для больших контуров, но контур должен помещаться в полусферу.
+type Ring []Point
+
+// Polygon описывает многоугольник: первый контур - внешняя граница, остальные - "дыры".
+type Polygon []Ring
+
+// MultiPolygon описывает набор многоугольников.
+type MultiPolygon []Polygon
+
+// vertices возвращает вершины контура без повторения первой точки в конце.
+func (r Ring) vertices() []vector {
+	n := len(r)
+	if n > 1 && r[0] == r[n-1] {
+		n--
+	}
+	vs := make([]vector, n)
+	for i := range vs {
+		vs[i] = toVector(r[i])
+	}
+	return vs
+}
+
+// center возвращает направление на усредненный центр вершин контура.
+func center(vs []vector) vector {
+	var c vector
+	for _, v := range vs {
+		c = c.add(v)
+	}
+	return c.normalize()
+}
+
+// Contains возвращает true, если точка находится внутри контура. Для проверки используется
+// гномоническая проекция с центром в середине контура, в которой дуги большого круга
+// отображаются в отрезки прямых.
+func (r Ring) Contains(p Point) bool {
+	vs := r.vertices()
+	if len(vs) < 3 {
+		return false
+	}
+	c := center(vs)
+	// базис касательной плоскости в центре контура
+	e := vector{0, 0, 1}.cross(c)
+	if e.norm() < 1e-12 { // центр на полюсе
+		e = vector{0, 1, 0}
+	}
+	e = e.normalize()
+	n := c.cross(e)
+	project := func(v vector) (float64, float64, bool) {
+		d := v.dot(c)
+		return v.dot(e) / d, v.dot(n) / d, d > 0
+	}
+	px, py, ok := project(toVector(p))
+	if !ok {
+		return false // точка на другой полусфере
+	}
+	var inside bool
+	xj, yj, _ := project(vs[len(vs)-1])
+	for _, v := range vs {
+		xi, yi, ok := project(v)
+		if !ok {
+			return false // контур не помещается в полусферу
+		}
+		if (yi > py) != (yj > py) && px < (xj-xi)*(py-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+		xj, yj = xi, yi
+	}
+	return inside
+}
+
+// excess возвращает знаковые сферические избытки треугольников, образованных точкой c и каждым
+// ребром контура, по одному на ребро. Знак зависит от направления обхода контура.
+func excess(c vector, vs []vector) []float64 {
+	es := make([]float64, len(vs))
+	for i, a := range vs {
+		b := vs[(i+1)%len(vs)]
+		es[i] = 2 * math.Atan2(c.dot(a.cross(b)), 1+c.dot(a)+a.dot(b)+b.dot(c))
+	}
+	return es
+}
+
+// Area возвращает площадь, ограниченную контуром, в квадратных километрах.
+func (r Ring) Area() float64 {
+	vs := r.vertices()
+	if len(vs) < 3 {
+		return 0
+	}
+	var sum float64
+	for _, e := range excess(center(vs), vs) {
+		sum += e
+	}
+	return math.Abs(sum) * erath_radius * erath_radius
+}
+
+// Clockwise возвращает true, если контур обходится по часовой стрелке (при взгляде на сферу
+// снаружи).
+func (r Ring) Clockwise() bool {
+	vs := r.vertices()
+	if len(vs) < 3 {
+		return false
+	}
+	var sum float64
+	for _, e := range excess(center(vs), vs) {
+		sum += e
+	}
+	return sum < 0
+}
+
+// Perimeter возвращает длину контура в километрах.
+func (r Ring) Perimeter() float64 {
+	n := len(r)
+	if n < 2 {
+		return 0
+	}
+	var length float64
+	for i := 1; i < n; i++ {
+		length += r[i-1].Distance(r[i])
+	}
+	if r[0] != r[n-1] {
+		length += r[n-1].Distance(r[0])
+	}
+	return length
+}
+
+// DistanceToEdge возвращает расстояние в километрах от точки до ближайшего ребра контура.
+func (r Ring) DistanceToEdge(p Point) float64 {
+	vs := r.vertices()
+	if len(vs) == 0 {
+		return math.NaN()
+	}
+	pv := toVector(p)
+	dist := math.Inf(1)
+	for i, a := range vs {
+		dist = math.Min(dist, arcDistance(pv, a, vs[(i+1)%len(vs)]))
+	}
+	return dist
+}
+
+// Contains возвращает true, если точка находится внутри внешнего контура и не попадает
+// ни в одну из "дыр" многоугольника.
+func (pg Polygon) Contains(p Point) bool {
+	if len(pg) == 0 || !pg[0].Contains(p) {
+		return false
+	}
+	for _, hole := range pg[1:] {
+		if hole.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Area возвращает площадь многоугольника в квадратных километрах за вычетом "дыр".
+func (pg Polygon) Area() float64 {
+	if len(pg) == 0 {
+		return 0
+	}
+	area := pg[0].Area()
+	for _, hole := range pg[1:] {
+		area -= hole.Area()
+	}
+	return area
+}
+
+// Perimeter возвращает суммарную длину всех контуров многоугольника в километрах.
+func (pg Polygon) Perimeter() float64 {
+	var length float64
+	for _, r := range pg {
+		length += r.Perimeter()
+	}
+	return length
+}
+
+// DistanceToEdge возвращает расстояние в километрах от точки до ближайшей границы
+// многоугольника, включая границы "дыр".
+func (pg Polygon) DistanceToEdge(p Point) float64 {
+	dist := math.NaN()
+	for _, r := range pg {
+		if d := r.DistanceToEdge(p); math.IsNaN(dist) || d < dist {
+			dist = d
+		}
+	}
+	return dist
+}
+
+// BBox возвращает область, ограничивающую контур. Учитываются дуги ребер, выходящие за
+// широты вершин, а если контур охватывает полюс, то область доходит до него и включает все
+// долготы.
+func (r Ring) BBox() BBox {
+	b := NewBBox(r...)
+	vs := r.vertices()
+	if len(vs) < 2 {
+		return b
+	}
+	for i, a := range vs {
+		c := vs[(i+1)%len(vs)]
+		n := a.cross(c)
+		if n.norm() < 1e-12 {
+			continue
+		}
+		n = n.normalize()
+		// самая северная точка большого круга ребра; самая южная - противоположная ей
+		top := vector{0, 0, 1}.add(n.scale(-n[2]))
+		if top.norm() < 1e-12 { // ребро лежит на экваторе
+			continue
+		}
+		top = top.normalize()
+		for _, v := range []vector{top, top.scale(-1)} {
+			if a.cross(v).dot(n) >= 0 && v.cross(c).dot(n) >= 0 {
+				lat := v.point().Lat()
+				b.South, b.North = math.Min(b.South, lat), math.Max(b.North, lat)
+			}
+		}
+	}
+	if len(vs) >= 3 {
+		if r.Contains(Point{90, 0}) {
+			b.North, b.West, b.East = 90, -180, 180
+		}
+		if r.Contains(Point{-90, 0}) {
+			b.South, b.West, b.East = -90, -180, 180
+		}
+	}
+	return b
+}
+
+// BBox возвращает область, ограничивающую внешний контур многоугольника.
+func (pg Polygon) BBox() BBox {
+	if len(pg) == 0 {
+		return EmptyBBox
+	}
+	return pg[0].BBox()
+}
+
+// centroid возвращает первый момент многоугольника: интеграл радиус-вектора по его площади на
+// единичной сфере. По теореме Стокса он равен половине суммы по ребрам длины дуги, умноженной
+// на единичную нормаль к ее плоскости.
+func (pg Polygon) centroid() vector {
+	var sum vector
+	for i, r := range pg {
+		vs := r.vertices()
+		if len(vs) < 3 {
+			continue
+		}
+		var total float64
+		for _, e := range excess(center(vs), vs) {
+			total += e
+		}
+		// площадь внешнего контура положительна, а "дыр" - отрицательна
+		sign := math.Copysign(1, total)
+		if i > 0 {
+			sign = -sign
+		}
+		for j, a := range vs {
+			b := vs[(j+1)%len(vs)]
+			sum = sum.add(a.cross(b).normalize().scale(sign * a.angle(b) / 2))
+		}
+	}
+	return sum
+}
+
+// Centroid возвращает центр масс многоугольника на сфере.
+func (pg Polygon) Centroid() Point {
+	c := pg.centroid()
+	if c.norm() == 0 {
+		return NaNPoint
+	}
+	return c.point()
+}
+
+// Contains возвращает true, если точка находится внутри одного из многоугольников.
+func (mp MultiPolygon) Contains(p Point) bool {
+	for _, pg := range mp {
+		if pg.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Area возвращает суммарную площадь многоугольников в квадратных километрах.
+func (mp MultiPolygon) Area() float64 {
+	var area float64
+	for _, pg := range mp {
+		area += pg.Area()
+	}
+	return area
+}
+
+// Perimeter возвращает суммарную длину всех контуров в километрах.
+func (mp MultiPolygon) Perimeter() float64 {
+	var length float64
+	for _, pg := range mp {
+		length += pg.Perimeter()
+	}
+	return length
+}
+
+// DistanceToEdge возвращает расстояние в километрах от точки до ближайшей границы.
+func (mp MultiPolygon) DistanceToEdge(p Point) float64 {
+	dist := math.NaN()
+	for _, pg := range mp {
+		if d := pg.DistanceToEdge(p); math.IsNaN(dist) || d < dist {
+			dist = d
+		}
+	}
+	return dist
+}
+
+// Centroid возвращает общий центр масс многоугольников на сфере.
+func (mp MultiPolygon) Centroid() Point {
+	var sum vector
+	for _, pg := range mp {
+		sum = sum.add(pg.centroid())
+	}
+	if sum.norm() == 0 {
+		return NaNPoint
+	}
+	return sum.point()
+}
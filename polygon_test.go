@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonOctant(t *testing.T) {
+	// восьмая часть сферы: треугольник с тремя прямыми углами
+	octant := Polygon{{{0, 0}, {0, 90}, {90, 0}}}
+	if a, want := octant.Area(), math.Pi*erath_radius*erath_radius/2; math.Abs(a-want) > 1e-6*want {
+		t.Errorf("bad area: %f, want %f", a, want)
+	}
+	if p, want := octant.Perimeter(), 3*math.Pi*erath_radius/2; math.Abs(p-want) > 1e-6 {
+		t.Errorf("bad perimeter: %f, want %f", p, want)
+	}
+	if !octant.Contains(NewPoint(30, 30)) || octant.Contains(NewPoint(30, 100)) {
+		t.Error("bad contains")
+	}
+	c := octant.Centroid()
+	if math.Abs(c.Lon()-45) > 1e-9 || math.Abs(c.Lat()-35.26439) > 1e-3 {
+		t.Errorf("bad centroid: %v", c)
+	}
+	// обратный порядок обхода не влияет на результат
+	reversed := Polygon{{{90, 0}, {0, 90}, {0, 0}}}
+	if math.Abs(reversed.Area()-octant.Area()) > 1e-6 || reversed.Centroid().Distance(c) > 1e-6 {
+		t.Error("orientation changes result")
+	}
+}
+
+func TestPolygonCentroidLarge(t *testing.T) {
+	// ожидаемое значение получено численным интегрированием по мелкому разбиению
+	// многоугольника на сферические треугольники
+	pg := Polygon{{{0, 0}, {0, 60}, {40, 100}, {75, 30}, {30, -10}}}
+	if c := pg.Centroid(); math.Abs(c.Lat()-34.529298) > 1e-5 || math.Abs(c.Lon()-38.139686) > 1e-5 {
+		t.Errorf("bad centroid: %v", c)
+	}
+}
+
+func TestPolygonHole(t *testing.T) {
+	square := Ring{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}
+	if a := square.Area(); math.Abs(a-12363.5) > 2 {
+		t.Errorf("bad area: %f", a)
+	}
+	hole := Ring{{0.25, 0.25}, {0.25, 0.75}, {0.75, 0.75}, {0.75, 0.25}}
+	pg := Polygon{square, hole}
+	if a := pg.Area(); math.Abs(a-0.75*square.Area()) > 2 {
+		t.Errorf("bad area with hole: %f", a)
+	}
+	if !pg.Contains(NewPoint(0.1, 0.1)) || pg.Contains(NewPoint(0.5, 0.5)) {
+		t.Error("bad contains with hole")
+	}
+	if d := square.DistanceToEdge(NewPoint(0.5, 0.5)); math.Abs(d-55.6) > 0.1 {
+		t.Errorf("bad distance to edge: %f", d)
+	}
+	if d := pg.DistanceToEdge(NewPoint(0.5, 0.5)); math.Abs(d-27.8) > 0.1 {
+		t.Errorf("bad distance to hole edge: %f", d)
+	}
+	if d := square.DistanceToEdge(NewPoint(0.5, 2)); math.Abs(d-111.2) > 0.1 {
+		t.Errorf("bad distance to edge from outside: %f", d)
+	}
+	if c := pg.Centroid(); c.Distance(NewPoint(0.5, 0.5)) > 0.01 {
+		t.Errorf("bad centroid: %v", c)
+	}
+	mp := MultiPolygon{pg, {{{10, 10}, {10, 11}, {11, 11}, {11, 10}}}}
+	if !mp.Contains(NewPoint(10.5, 10.5)) || mp.Contains(NewPoint(5, 5)) {
+		t.Error("bad multipolygon contains")
+	}
+	if a := mp.Area(); a <= pg.Area() {
+		t.Errorf("bad multipolygon area: %f", a)
+	}
+}
+
+func TestPolygonAntimeridian(t *testing.T) {
+	chukotka := Polygon{{{64, 175}, {64, -170}, {70, -170}, {70, 175}}}
+	if !chukotka.Contains(NewPoint(67, 180)) || !chukotka.Contains(NewPoint(67, -175)) ||
+		chukotka.Contains(NewPoint(67, 0)) || chukotka.Contains(NewPoint(67, 170)) {
+		t.Error("bad contains")
+	}
+	if c := chukotka.Centroid(); math.Abs(c.Lon()-(-177.5)) > 1e-6 {
+		t.Errorf("bad centroid: %v", c)
+	}
+	if b := chukotka.BBox(); !b.CrossesAntimeridian() {
+		t.Errorf("bad bbox: %v", b)
+	}
+}
+
+func TestPolygonBBox(t *testing.T) {
+	// северное ребро по дуге большого круга уходит выше широты вершин
+	pg := Polygon{{{60, -60}, {60, 60}, {50, 60}, {50, -60}}}
+	b := pg.BBox()
+	if math.Abs(b.North-73.897886) > 1e-6 || b.South != 50 || b.West != -60 || b.East != 60 {
+		t.Errorf("bad bbox with bulging edge: %v", b)
+	}
+	if p := NewPoint(70, 0); !pg.Contains(p) || !b.Contains(p) {
+		t.Errorf("%v is not inside bbox %v", p, b)
+	}
+	// контур вокруг полюса
+	pg = Polygon{{{80, 0}, {80, 90}, {80, 180}, {80, -90}}}
+	if b := pg.BBox(); b.North != 90 || b.South != 80 || b.West != -180 || b.East != 180 {
+		t.Errorf("bad bbox around north pole: %v", b)
+	}
+	pg = Polygon{{{-70, 0}, {-70, -120}, {-70, 120}}}
+	if b := pg.BBox(); b.South != -90 || b.North >= -70+1e-9 || b.West != -180 || b.East != 180 {
+		t.Errorf("bad bbox around south pole: %v", b)
+	}
+}
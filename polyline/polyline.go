@@ -0,0 +1,155 @@
+// Package polyline реализует кодирование списка точек в формат Google Encoded Polyline.
+// https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+//
+// Кроме координат, для треков поддерживается дополнительный канал с временем каждой точки:
+// он кодируется третьим значением после широты и долготы как разница в секундах с предыдущей
+// точкой (для первой точки - время Unix).
+package polyline
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mdigger/geo"
+)
+
+// Точность кодирования координат: множитель для градусов перед округлением до целого.
+const (
+	Precision5 = 1e5 // стандартная точность Google (около 1 м)
+	Precision6 = 1e6 // точность OSRM и Valhalla (около 10 см)
+)
+
+// ErrBadPolyline возвращается при разборе некорректной строки.
+var ErrBadPolyline = errors.New("polyline: bad encoded string")
+
+// Encode кодирует список точек с указанной точностью (Precision5 или Precision6). Если среди
+// точек есть пустые (geo.NaNPoint) или с координатами вне допустимых пределов, то возвращается
+// ошибка.
+func Encode(points []geo.Point, precision float64) (string, error) {
+	buf, err := encode(points, nil, precision)
+	return string(buf), err
+}
+
+// EncodeTrack кодирует трек: список точек и время каждой из них с точностью до секунды.
+// Количество элементов в times должно совпадать с количеством точек. Точки проверяются так же,
+// как в Encode.
+func EncodeTrack(points []geo.Point, times []time.Time, precision float64) (string, error) {
+	if len(times) != len(points) {
+		return "", errors.New("polyline: points and times length mismatch")
+	}
+	buf, err := encode(points, times, precision)
+	return string(buf), err
+}
+
+func encode(points []geo.Point, times []time.Time, precision float64) ([]byte, error) {
+	var (
+		buf              []byte
+		prevLat, prevLon int64
+		prevTime         int64
+	)
+	for i, p := range points {
+		if _, err := geo.MakePoint(p.Lat(), p.Lon()); err != nil {
+			return nil, fmt.Errorf("polyline: point %d: %w", i, err)
+		}
+		lat := int64(math.Round(p.Lat() * precision))
+		lon := int64(math.Round(p.Lon() * precision))
+		buf = encodeValue(buf, lat-prevLat)
+		buf = encodeValue(buf, lon-prevLon)
+		prevLat, prevLon = lat, lon
+		if times != nil {
+			t := times[i].Unix()
+			buf = encodeValue(buf, t-prevTime)
+			prevTime = t
+		}
+	}
+	return buf, nil
+}
+
+// encodeValue добавляет к буферу закодированное значение.
+func encodeValue(buf []byte, v int64) []byte {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		buf = append(buf, byte(0x20|u&0x1f)+63)
+		u >>= 5
+	}
+	return append(buf, byte(u)+63)
+}
+
+// Decode декодирует строку в список точек с указанной точностью.
+func Decode(s string, precision float64) ([]geo.Point, error) {
+	points, _, err := decode(s, precision, false)
+	return points, err
+}
+
+// DecodeTrack декодирует строку, полученную EncodeTrack, в список точек и время каждой из них.
+func DecodeTrack(s string, precision float64) ([]geo.Point, []time.Time, error) {
+	return decode(s, precision, true)
+}
+
+func decode(s string, precision float64, withTime bool) ([]geo.Point, []time.Time, error) {
+	var (
+		points      []geo.Point
+		times       []time.Time
+		lat, lon, t int64
+		pos         int
+	)
+	for pos < len(s) {
+		var dLat, dLon, dTime int64
+		var err error
+		if dLat, pos, err = decodeValue(s, pos); err != nil {
+			return nil, nil, err
+		}
+		if dLon, pos, err = decodeValue(s, pos); err != nil {
+			return nil, nil, err
+		}
+		lat, lon = lat+dLat, lon+dLon
+		p, err := geo.MakePoint(float64(lat)/precision, float64(lon)/precision)
+		if err != nil {
+			return nil, nil, ErrBadPolyline
+		}
+		points = append(points, p)
+		if withTime {
+			if dTime, pos, err = decodeValue(s, pos); err != nil {
+				return nil, nil, err
+			}
+			t += dTime
+			times = append(times, time.Unix(t, 0).UTC())
+		}
+	}
+	return points, times, nil
+}
+
+// decodeValue декодирует значение, начинающееся с указанной позиции, и возвращает его вместе
+// с позицией следующего значения.
+func decodeValue(s string, pos int) (int64, int, error) {
+	var (
+		u     uint64
+		shift uint
+	)
+	for {
+		if pos >= len(s) || shift > 60 {
+			return 0, pos, ErrBadPolyline
+		}
+		b := s[pos]
+		if b < 63 || b > 63+0x3f {
+			return 0, pos, ErrBadPolyline
+		}
+		b -= 63
+		pos++
+		u |= uint64(b&0x1f) << shift
+		shift += 5
+		if b < 0x20 {
+			break
+		}
+	}
+	v := int64(u >> 1)
+	if u&1 != 0 {
+		v = ^v
+	}
+	return v, pos, nil
+}
@@ -0,0 +1,88 @@
+package polyline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdigger/geo"
+)
+
+func TestGoogleExample(t *testing.T) {
+	// пример из документации Google
+	points := []geo.Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	const encoded = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	if s, err := Encode(points, Precision5); s != encoded || err != nil {
+		t.Errorf("bad encoding: %s %v", s, err)
+	}
+	decoded, err := Decode(encoded, Precision5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, points) {
+		t.Errorf("bad decoding: %v", decoded)
+	}
+}
+
+func TestPrecision6(t *testing.T) {
+	points := []geo.Point{{55.755812, 37.617321}, {55.755913, 37.617108}, {-33.856812, 151.215312}}
+	s, err := Encode(points, Precision6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(s, Precision6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range decoded {
+		if p.Distance(points[i]) > 1e-6 {
+			t.Errorf("bad point %d: %v", i, p)
+		}
+	}
+	// с точностью 1e5 координаты округляются до 5 знаков
+	s, _ = Encode(points, Precision5)
+	decoded, _ = Decode(s, Precision5)
+	if decoded[0] != geo.NewPoint(55.75581, 37.61732) {
+		t.Errorf("bad rounding: %v", decoded[0])
+	}
+}
+
+func TestTrack(t *testing.T) {
+	start := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	points := []geo.Point{{55.7558, 37.6173}, {55.7560, 37.6180}, {55.7571, 37.6195}}
+	times := []time.Time{start, start.Add(5 * time.Second), start.Add(65 * time.Second)}
+	s, err := EncodeTrack(points, times, Precision5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, decodedTimes, err := DecodeTrack(s, Precision5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, points) || !reflect.DeepEqual(decodedTimes, times) {
+		t.Errorf("bad track: %v %v", decoded, decodedTimes)
+	}
+	if _, err := EncodeTrack(points, times[:1], Precision5); err == nil {
+		t.Error("expected length mismatch error")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, s := range []string{"_p~iF", "_p~iF~ps|", "_p~iF ps|U", "~~~~~~~~~~~~~~~~?"} {
+		if points, err := Decode(s, Precision5); err == nil {
+			t.Errorf("%q: expected error, got %v", s, points)
+		}
+	}
+	if points, err := Decode("", Precision5); err != nil || len(points) != 0 {
+		t.Error("bad empty polyline:", points, err)
+	}
+	for _, p := range []geo.Point{geo.NaNPoint, {91, 0}, {0, -181}} {
+		if s, err := Encode([]geo.Point{{55.75, 37.62}, p}, Precision5); err == nil {
+			t.Errorf("%v: expected error, got %q", p, s)
+		}
+	}
+	start := time.Unix(0, 0)
+	if _, err := EncodeTrack([]geo.Point{geo.NaNPoint}, []time.Time{start}, Precision5); err == nil {
+		t.Error("expected error for empty track point")
+	}
+}
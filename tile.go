@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Tile описывает тайл веб-карты в проекции Web Mercator (EPSG:3857) в схеме XYZ, используемой
+// OpenStreetMap и Google: X растет на восток от 180° западной долготы, Y - на юг от северной
+// границы проекции.
+type Tile struct {
+	X, Y int // номер тайла по горизонтали и вертикали
+	Z    int // масштаб (zoom)
+}
+
+const (
+	// TileSize задает размер тайла в пикселях.
+	TileSize = 256
+	// MaxZoom задает максимальный поддерживаемый масштаб.
+	MaxZoom = 30
+	// MaxMercatorLat задает широту северной и южной границы проекции Web Mercator.
+	MaxMercatorLat = 85.05112877980659
+)
+
+// clampZoom ограничивает масштаб допустимыми значениями.
+func clampZoom(zoom int) int {
+	if zoom < 0 {
+		return 0
+	}
+	if zoom > MaxZoom {
+		return MaxZoom
+	}
+	return zoom
+}
+
+// mercator возвращает координаты точки в проекции Web Mercator, нормализованные к [0, 1].
+// Широта ограничивается границами проекции.
+func mercator(p Point) (x, y float64) {
+	lat := math.Max(-MaxMercatorLat, math.Min(MaxMercatorLat, p.Lat()))
+	sinLat := math.Sin(lat * (math.Pi / 180.0))
+	x = (p.Lon() + 180) / 360
+	y = 0.5 - math.Log((1+sinLat)/(1-sinLat))/(4*math.Pi)
+	return x, y
+}
+
+// Pixel возвращает глобальные пиксельные координаты точки на карте указанного масштаба:
+// карта занимает TileSize*2^zoom пикселей по каждой оси. Масштаб ограничивается
+// диапазоном от 0 до MaxZoom.
+func (p Point) Pixel(zoom int) (x, y float64) {
+	size := float64(TileSize) * math.Exp2(float64(clampZoom(zoom)))
+	x, y = mercator(p)
+	return x * size, y * size
+}
+
+// Tile возвращает тайл указанного масштаба, содержащий точку. Масштаб ограничивается
+// диапазоном от 0 до MaxZoom.
+func (p Point) Tile(zoom int) Tile {
+	zoom = clampZoom(zoom)
+	n := 1 << uint(zoom)
+	x, y := mercator(p)
+	t := Tile{X: int(math.Floor(x * float64(n))), Y: int(math.Floor(y * float64(n))), Z: zoom}
+	// долгота 180° и южная граница проекции относятся к последнему тайлу, а погрешность
+	// вычислений на северной границе не должна выводить за пределы первого
+	t.X = clampTile(t.X, n)
+	t.Y = clampTile(t.Y, n)
+	return t
+}
+
+// clampTile ограничивает номер тайла диапазоном от 0 до n-1.
+func clampTile(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// tileLat возвращает широту горизонтальной границы тайлов с номером y на масштабе с n тайлами.
+func tileLat(y, n int) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/float64(n)))) * (180.0 / math.Pi)
+}
+
+// BBox возвращает границы тайла.
+func (t Tile) BBox() BBox {
+	n := 1 << uint(t.Z)
+	return BBox{
+		South: tileLat(t.Y+1, n),
+		West:  float64(t.X)/float64(n)*360 - 180,
+		North: tileLat(t.Y, n),
+		East:  float64(t.X+1)/float64(n)*360 - 180,
+	}
+}
+
+// Pixel возвращает пиксельные координаты точки относительно левого верхнего угла тайла.
+// Для точек за пределами тайла координаты выходят за диапазон [0, TileSize).
+func (t Tile) Pixel(p Point) (x, y float64) {
+	x, y = p.Pixel(t.Z)
+	return x - float64(t.X*TileSize), y - float64(t.Y*TileSize)
+}
+
+// Quadkey возвращает ключ тайла в схеме Bing Maps: по одной цифре от 0 до 3 на каждый уровень
+// масштаба. Для масштаба 0 возвращается пустая строка.
+// https://msdn.microsoft.com/en-us/library/bb259689.aspx
+func (t Tile) Quadkey() string {
+	key := make([]byte, t.Z)
+	for i := 0; i < t.Z; i++ {
+		mask := 1 << uint(t.Z-1-i)
+		digit := byte('0')
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		key[i] = digit
+	}
+	return string(key)
+}
+
+// ParseQuadkey возвращает тайл по ключу в схеме Bing Maps.
+func ParseQuadkey(key string) (Tile, error) {
+	if len(key) > MaxZoom {
+		return Tile{}, fmt.Errorf("quadkey too long: %q", key)
+	}
+	t := Tile{Z: len(key)}
+	for i := 0; i < len(key); i++ {
+		digit := key[i] - '0'
+		if digit > 3 {
+			return Tile{}, fmt.Errorf("bad quadkey: %q", key)
+		}
+		t.X = t.X<<1 | int(digit&1)
+		t.Y = t.Y<<1 | int(digit>>1)
+	}
+	return t, nil
+}
+
+// Parent возвращает тайл предыдущего масштаба, содержащий данный. Для масштаба 0 возвращается
+// сам тайл.
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+	return Tile{X: t.X >> 1, Y: t.Y >> 1, Z: t.Z - 1}
+}
+
+// Children возвращает четыре тайла следующего масштаба, из которых состоит данный, в порядке
+// ключей Bing Maps.
+func (t Tile) Children() [4]Tile {
+	x, y, z := t.X<<1, t.Y<<1, t.Z+1
+	return [4]Tile{{x, y, z}, {x + 1, y, z}, {x, y + 1, z}, {x + 1, y + 1, z}}
+}
+
+// String возвращает строковое представление тайла в виде "z/x/y", как в URL тайловых серверов.
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// ParseTile разбирает строку в формате "z/x/y".
+func ParseTile(s string) (Tile, error) {
+	var t Tile
+	if n, err := fmt.Sscanf(strings.TrimSpace(s), "%d/%d/%d", &t.Z, &t.X, &t.Y); err != nil || n != 3 {
+		return Tile{}, fmt.Errorf("bad tile: %q", s)
+	}
+	if t.Z < 0 || t.Z > MaxZoom || t.X < 0 || t.Y < 0 || t.X >= 1<<uint(t.Z) || t.Y >= 1<<uint(t.Z) {
+		return Tile{}, fmt.Errorf("bad tile: %q", s)
+	}
+	return t, nil
+}
+
+// Tiles вызывает fn для каждого тайла указанного масштаба, пересекающегося с областью, по
+// строкам с севера на юг и с запада на восток. Перебор прекращается, если fn возвращает
+// false. Области, пересекающие линию перемены дат, обрабатываются корректно; широта
+// ограничивается границами проекции.
+func (b BBox) Tiles(zoom int, fn func(Tile) bool) {
+	if b.IsEmpty() {
+		return
+	}
+	zoom = clampZoom(zoom)
+	n := 1 << uint(zoom)
+	nw := Point{b.North, b.West}.Tile(zoom)
+	se := Point{b.South, b.East}.Tile(zoom)
+	columns := se.X - nw.X + 1
+	if b.CrossesAntimeridian() {
+		columns += n
+	}
+	if columns > n {
+		columns = n
+	}
+	for y := nw.Y; y <= se.Y; y++ {
+		for i := 0; i < columns; i++ {
+			if !fn(Tile{X: (nw.X + i) % n, Y: y, Z: zoom}) {
+				return
+			}
+		}
+	}
+}
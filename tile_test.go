@@ -0,0 +1,112 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTile(t *testing.T) {
+	for _, test := range []struct {
+		point   Point
+		zoom    int
+		tile    Tile
+		quadkey string
+	}{
+		{Point{0, 0}, 0, Tile{0, 0, 0}, ""},
+		{Point{0, 0}, 1, Tile{1, 1, 1}, "3"},
+		{Point{55.7539, 37.6208}, 10, Tile{619, 320, 10}, "1203101011"},
+		{Point{-33.8568, 151.2153}, 12, Tile{3768, 2457, 12}, "311230133002"},
+		{Point{90, 180}, 3, Tile{7, 0, 3}, "111"},
+		{Point{-90, -180}, 3, Tile{0, 7, 3}, "222"},
+	} {
+		tile := test.point.Tile(test.zoom)
+		if tile != test.tile {
+			t.Errorf("%v: %v, want %v", test.point, tile, test.tile)
+		}
+		if key := tile.Quadkey(); key != test.quadkey {
+			t.Errorf("%v: quadkey %q, want %q", tile, key, test.quadkey)
+		}
+		if tile2, err := ParseQuadkey(test.quadkey); err != nil || tile2 != tile {
+			t.Errorf("%q: %v %v", test.quadkey, tile2, err)
+		}
+		if tile2, err := ParseTile(tile.String()); err != nil || tile2 != tile {
+			t.Errorf("%s: %v %v", tile, tile2, err)
+		}
+		if b := tile.BBox(); math.Abs(test.point.Lat()) < MaxMercatorLat && !b.Contains(test.point) {
+			t.Errorf("%v: %v does not contain point", tile, b)
+		}
+		x, y := tile.Pixel(test.point)
+		if x < -1e-6 || x > TileSize+1e-6 || y < -1e-6 || y > TileSize+1e-6 {
+			t.Errorf("%v: bad pixel %f %f", tile, x, y)
+		}
+	}
+	b := Tile{0, 0, 0}.BBox()
+	if math.Abs(b.North-MaxMercatorLat) > 1e-9 || b.South != -b.North || b.West != -180 || b.East != 180 {
+		t.Errorf("bad world bbox: %v", b)
+	}
+	if x, y := (Point{0, 0}).Pixel(2); x != 512 || y != 512 {
+		t.Errorf("bad pixel: %f %f", x, y)
+	}
+	parent := Tile{619, 320, 10}.Parent()
+	if parent != (Tile{309, 160, 9}) {
+		t.Errorf("bad parent: %v", parent)
+	}
+	for i, child := range parent.Children() {
+		if child.Parent() != parent || child.Quadkey() != parent.Quadkey()+string('0'+byte(i)) {
+			t.Errorf("bad child %d: %v", i, child)
+		}
+	}
+	for _, s := range []string{"4", "1204", "x"} {
+		if _, err := ParseQuadkey(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+	for _, s := range []string{"1/2/0", "-1/0/0", "a/b/c", "3/1"} {
+		if _, err := ParseTile(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestBBoxTiles(t *testing.T) {
+	count := func(b BBox, zoom int) (tiles []Tile) {
+		b.Tiles(zoom, func(tile Tile) bool {
+			tiles = append(tiles, tile)
+			return true
+		})
+		return tiles
+	}
+	world := BBox{-90, -180, 90, 180}
+	if n := len(count(world, 3)); n != 64 {
+		t.Errorf("world at zoom 3: %d tiles", n)
+	}
+	// Москва в пределах МКАД
+	moscow := BBox{55.57, 37.36, 55.91, 37.85}
+	tiles := count(moscow, 12)
+	if len(tiles) == 0 {
+		t.Fatal("no tiles")
+	}
+	for _, tile := range tiles {
+		if !tile.BBox().Intersects(moscow) {
+			t.Errorf("%v does not intersect bbox", tile)
+		}
+	}
+	nw, se := Point{55.91, 37.36}.Tile(12), Point{55.57, 37.85}.Tile(12)
+	if len(tiles) != (se.X-nw.X+1)*(se.Y-nw.Y+1) || tiles[0] != nw || tiles[len(tiles)-1] != se {
+		t.Errorf("bad tiles: %v", tiles)
+	}
+	// область через линию перемены дат
+	tiles = count(BBox{-10, 170, 10, -170}, 4)
+	if len(tiles) != 4 || tiles[0].X != 15 || tiles[1].X != 0 {
+		t.Errorf("bad antimeridian tiles: %v", tiles)
+	}
+	// остановка перебора
+	var n int
+	world.Tiles(5, func(Tile) bool { n++; return n < 10 })
+	if n != 10 {
+		t.Errorf("iteration not stopped: %d", n)
+	}
+	if len(count(EmptyBBox, 3)) != 0 {
+		t.Error("tiles for empty bbox")
+	}
+}
@@ -0,0 +1,96 @@
+package geo
+
+import "math"
+
+// transverseMercator описывает поперечную проекцию Меркатора на эллипсоиде. Используются
+// ряды Крюгера до n^6, точность которых лучше миллиметра в пределах ±3900 км от осевого
+// меридиана.
+// https://arxiv.org/abs/1002.1417 (C.F.F. Karney, Transverse Mercator with an accuracy of a few nanometers)
+type transverseMercator struct {
+	e     float64    // первый эксцентриситет эллипсоида
+	A     float64    // радиус спрямляющей сферы
+	alpha [6]float64 // коэффициенты прямого преобразования
+	beta  [6]float64 // коэффициенты обратного преобразования
+}
+
+// newTransverseMercator возвращает проекцию для указанного эллипсоида.
+func newTransverseMercator(ellipsoid Ellipsoid) *transverseMercator {
+	n := ellipsoid.F / (2 - ellipsoid.F)
+	n2, n3 := n*n, n*n*n
+	n4, n5, n6 := n3*n, n3*n2, n3*n3
+	return &transverseMercator{
+		e: math.Sqrt(ellipsoid.E2()),
+		A: ellipsoid.A / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+		alpha: [6]float64{
+			n/2 - 2.0/3*n2 + 5.0/16*n3 + 41.0/180*n4 - 127.0/288*n5 + 7891.0/37800*n6,
+			13.0/48*n2 - 3.0/5*n3 + 557.0/1440*n4 + 281.0/630*n5 - 1983433.0/1935360*n6,
+			61.0/240*n3 - 103.0/140*n4 + 15061.0/26880*n5 + 167603.0/181440*n6,
+			49561.0/161280*n4 - 179.0/168*n5 + 6601661.0/7257600*n6,
+			34729.0/80640*n5 - 3418889.0/1995840*n6,
+			212378941.0 / 319334400 * n6,
+		},
+		beta: [6]float64{
+			n/2 - 2.0/3*n2 + 37.0/96*n3 - 1.0/360*n4 - 81.0/512*n5 + 96199.0/604800*n6,
+			1.0/48*n2 + 1.0/15*n3 - 437.0/1440*n4 + 46.0/105*n5 - 1118711.0/3870720*n6,
+			17.0/480*n3 - 37.0/840*n4 - 209.0/4480*n5 + 5569.0/90720*n6,
+			4397.0/161280*n4 - 11.0/504*n5 - 830251.0/7257600*n6,
+			4583.0/161280*n5 - 108847.0/3991680*n6,
+			20648693.0 / 638668800 * n6,
+		},
+	}
+}
+
+// forward возвращает прямоугольные координаты точки в метрах относительно пересечения
+// осевого меридиана lon0 с экватором (без учета ложных смещений) при масштабе k0.
+func (tm *transverseMercator) forward(p Point, lon0, k0 float64) (x, y float64) {
+	phi := p.Lat() * (math.Pi / 180.0)
+	lambda := normalizeLon(p.Lon()-lon0) * (math.Pi / 180.0)
+	e := tm.e
+	tau := math.Tan(phi)
+	sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+	tauP := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+	if math.Abs(phi) == math.Pi/2 { // на полюсе tan не определен
+		tauP = math.Copysign(math.Inf(1), phi)
+	}
+	sinL, cosL := math.Sincos(lambda)
+	xiP := math.Atan2(tauP, cosL)
+	etaP := math.Asinh(sinL / math.Sqrt(tauP*tauP+cosL*cosL))
+	xi, eta := xiP, etaP
+	for j, a := range tm.alpha {
+		k := 2 * float64(j+1)
+		xi += a * math.Sin(k*xiP) * math.Cosh(k*etaP)
+		eta += a * math.Cos(k*xiP) * math.Sinh(k*etaP)
+	}
+	return k0 * tm.A * eta, k0 * tm.A * xi
+}
+
+// inverse возвращает точку по прямоугольным координатам, полученным forward.
+func (tm *transverseMercator) inverse(x, y, lon0, k0 float64) Point {
+	eta := x / (k0 * tm.A)
+	xi := y / (k0 * tm.A)
+	xiP, etaP := xi, eta
+	for j, b := range tm.beta {
+		k := 2 * float64(j+1)
+		xiP -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaP -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	sinhEtaP := math.Sinh(etaP)
+	sinXiP, cosXiP := math.Sincos(xiP)
+	tauP := sinXiP / math.Sqrt(sinhEtaP*sinhEtaP+cosXiP*cosXiP)
+	// решаем уравнение для tau методом Ньютона
+	e, e2 := tm.e, tm.e*tm.e
+	tau := tauP
+	for i := 0; i < 20; i++ {
+		sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+		tauI := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+		delta := (tauP - tauI) / math.Sqrt(1+tauI*tauI) *
+			(1 + (1-e2)*tau*tau) / ((1 - e2) * math.Sqrt(1+tau*tau))
+		tau += delta
+		if math.Abs(delta) < 1e-12 {
+			break
+		}
+	}
+	lat := math.Atan(tau) * (180.0 / math.Pi)
+	lon := math.Atan2(sinhEtaP, cosXiP)*(180.0/math.Pi) + lon0
+	return Point{lat, normalizeLon(lon)}
+}
@@ -0,0 +1,225 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// UTM описывает координаты точки в системе UTM (Universal Transverse Mercator) на эллипсоиде
+// WGS84. В полярных областях (севернее 84° и южнее 80°) используется система UPS (Universal
+// Polar Stereographic): зона в этом случае равна 0, а полоса - A, B (юг) или Y, Z (север).
+type UTM struct {
+	Zone     int     // номер зоны (1-60) или 0 для UPS
+	Band     byte    // буква широтной полосы (C-X) или полярной зоны (A, B, Y, Z)
+	Easting  float64 // восточное смещение в метрах
+	Northing float64 // северное смещение в метрах
+}
+
+// Параметры проекций UTM и UPS.
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000
+	utmFalseNorthing = 10000000 // для южного полушария
+	upsScale         = 0.994
+	upsFalseOffset   = 2000000
+)
+
+// utmBands описывает буквы широтных полос UTM по 8° начиная с 80° южной широты.
+const utmBands = "CDEFGHJKLMNPQRSTUVWX"
+
+// ErrBadUTM возвращается при некорректных координатах UTM.
+var ErrBadUTM = errors.New("bad UTM coordinates")
+
+// utmProjection описывает проекцию Меркатора на эллипсоиде WGS84 для UTM.
+var utmProjection = newTransverseMercator(WGS84)
+
+// North возвращает true, если координаты относятся к северному полушарию.
+func (u UTM) North() bool {
+	return u.Band >= 'N'
+}
+
+// UPS возвращает true, если координаты относятся к полярной системе UPS.
+func (u UTM) UPS() bool {
+	return u.Band == 'A' || u.Band == 'B' || u.Band == 'Y' || u.Band == 'Z'
+}
+
+// utmZone возвращает номер зоны UTM для точки с учетом исключений для Норвегии и Шпицбергена.
+func utmZone(lat, lon float64) int {
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 60 // долгота 180°
+	}
+	switch {
+	case lat >= 56 && lat < 64 && lon >= 3 && lon < 12: // юго-западная Норвегия
+		zone = 32
+	case lat >= 72 && lat < 84 && lon >= 0 && lon < 42: // Шпицберген
+		switch {
+		case lon < 9:
+			zone = 31
+		case lon < 21:
+			zone = 33
+		case lon < 33:
+			zone = 35
+		default:
+			zone = 37
+		}
+	}
+	return zone
+}
+
+// utmBand возвращает букву широтной полосы UTM. Полоса X расширена до 84°.
+func utmBand(lat float64) byte {
+	i := int(math.Floor(lat/8 + 10))
+	if i > len(utmBands)-1 {
+		i = len(utmBands) - 1
+	}
+	return utmBands[i]
+}
+
+// UTM возвращает координаты точки в системе UTM, а в полярных областях - в системе UPS.
+func (p Point) UTM() (UTM, error) {
+	lat, lon := p.Lat(), p.Lon()
+	if !(lat >= -90 && lat <= 90) {
+		return UTM{}, ErrBadLatitude
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return UTM{}, ErrBadLongitude
+	}
+	if lat < -80 || lat >= 84 {
+		return p.ups(), nil
+	}
+	zone := utmZone(lat, lon)
+	x, y := utmProjection.forward(p, float64(zone-1)*6-180+3, utmScale)
+	u := UTM{Zone: zone, Band: utmBand(lat), Easting: x + utmFalseEasting, Northing: y}
+	if lat < 0 {
+		u.Northing += utmFalseNorthing
+	}
+	return u, nil
+}
+
+// upsConstant возвращает константу полярной стереографической проекции на WGS84.
+func upsConstant() (e, c float64) {
+	e = math.Sqrt(WGS84.E2())
+	c = 2 * WGS84.A * upsScale / math.Sqrt(math.Pow(1+e, 1+e)*math.Pow(1-e, 1-e))
+	return e, c
+}
+
+// ups возвращает координаты точки в системе UPS.
+func (p Point) ups() UTM {
+	e, c := upsConstant()
+	north := p.Lat() >= 0
+	phi := math.Abs(p.Lat()) * (math.Pi / 180.0)
+	lambda := p.Lon() * (math.Pi / 180.0)
+	sinPhi := math.Sin(phi)
+	t := math.Tan(math.Pi/4-phi/2) / math.Pow((1-e*sinPhi)/(1+e*sinPhi), e/2)
+	rho := c * t
+	sinL, cosL := math.Sincos(lambda)
+	u := UTM{Easting: upsFalseOffset + rho*sinL}
+	if north {
+		u.Northing = upsFalseOffset - rho*cosL
+		u.Band = 'Z'
+		if p.Lon() < 0 {
+			u.Band = 'Y'
+		}
+	} else {
+		u.Northing = upsFalseOffset + rho*cosL
+		u.Band = 'B'
+		if p.Lon() < 0 {
+			u.Band = 'A'
+		}
+	}
+	return u
+}
+
+// Point возвращает точку, соответствующую координатам UTM или UPS.
+func (u UTM) Point() (Point, error) {
+	if u.UPS() {
+		if u.Zone != 0 {
+			return NaNPoint, ErrBadUTM
+		}
+		return u.upsPoint()
+	}
+	if u.Zone < 1 || u.Zone > 60 || strings.IndexByte(utmBands, u.Band) < 0 {
+		return NaNPoint, ErrBadUTM
+	}
+	y := u.Northing
+	if !u.North() {
+		y -= utmFalseNorthing
+	}
+	p := utmProjection.inverse(u.Easting-utmFalseEasting, y, float64(u.Zone-1)*6-180+3, utmScale)
+	return MakePoint(p.Lat(), p.Lon())
+}
+
+// upsPoint возвращает точку по координатам UPS.
+func (u UTM) upsPoint() (Point, error) {
+	e, c := upsConstant()
+	north := u.North()
+	dx := u.Easting - upsFalseOffset
+	dy := u.Northing - upsFalseOffset
+	rho := math.Hypot(dx, dy)
+	t := rho / c
+	// широта определяется итерациями
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 20; i++ {
+		sinPhi := math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-e*sinPhi)/(1+e*sinPhi), e/2))
+		if math.Abs(next-phi) < 1e-12 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	var lambda float64
+	if north {
+		lambda = math.Atan2(dx, -dy)
+	} else {
+		lambda = math.Atan2(dx, dy)
+		phi = -phi
+	}
+	return MakePoint(phi*(180.0/math.Pi), lambda*(180.0/math.Pi))
+}
+
+// String возвращает строковое представление координат: зона с полосой, восточное и северное
+// смещение в метрах, например "37U 413265 6179944".
+func (u UTM) String() string {
+	if u.UPS() {
+		return fmt.Sprintf("%c %.0f %.0f", u.Band, u.Easting, u.Northing)
+	}
+	return fmt.Sprintf("%d%c %.0f %.0f", u.Zone, u.Band, u.Easting, u.Northing)
+}
+
+// ParseUTM разбирает строку с координатами UTM в формате, возвращаемом UTM.String.
+// Допускается указание полосы отдельно от номера зоны: "37 U 413265 6179944".
+func ParseUTM(s string) (UTM, error) {
+	fields := strings.Fields(strings.ToUpper(s))
+	if len(fields) == 4 { // зона и полоса указаны раздельно
+		fields = []string{fields[0] + fields[1], fields[2], fields[3]}
+	}
+	if len(fields) != 3 || len(fields[0]) == 0 {
+		return UTM{}, fmt.Errorf("bad UTM: %q", s)
+	}
+	var (
+		u   UTM
+		err error
+	)
+	zone := fields[0]
+	u.Band = zone[len(zone)-1]
+	if zone = zone[:len(zone)-1]; zone != "" {
+		if u.Zone, err = strconv.Atoi(zone); err != nil {
+			return UTM{}, fmt.Errorf("bad UTM zone: %q", s)
+		}
+	}
+	if u.Easting, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return UTM{}, fmt.Errorf("bad UTM easting: %q", s)
+	}
+	if u.Northing, err = strconv.ParseFloat(fields[2], 64); err != nil {
+		return UTM{}, fmt.Errorf("bad UTM northing: %q", s)
+	}
+	if _, err := u.Point(); err != nil {
+		return UTM{}, err
+	}
+	return u, nil
+}
@@ -0,0 +1,159 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestUTMReference(t *testing.T) {
+	for _, test := range []struct {
+		point Point
+		utm   UTM
+	}{
+		// пересечение экватора и нулевого меридиана
+		{Point{0, 0}, UTM{31, 'N', 166021.443, 0}},
+		// Эйфелева башня
+		{Point{48.8582, 2.2945}, UTM{31, 'U', 448251.795, 5411932.678}},
+		// Северный и Южный полюс: UPS
+		{Point{90, 0}, UTM{0, 'Z', 2000000, 2000000}},
+		{Point{-90, 0}, UTM{0, 'B', 2000000, 2000000}},
+	} {
+		u, err := test.point.UTM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Zone != test.utm.Zone || u.Band != test.utm.Band ||
+			math.Abs(u.Easting-test.utm.Easting) > 0.01 || math.Abs(u.Northing-test.utm.Northing) > 0.01 {
+			t.Errorf("%v: %v (%.3f %.3f), want %v", test.point, u, u.Easting, u.Northing, test.utm)
+		}
+		p, err := test.utm.Point()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Distance(test.point) > 1e-5 {
+			t.Errorf("%v: bad inverse: %v", test.utm, p)
+		}
+	}
+}
+
+func TestUTMZones(t *testing.T) {
+	for _, test := range []struct {
+		point Point
+		zone  string
+	}{
+		{Point{55.7539, 37.6208}, "37U"},   // Москва
+		{Point{-33.8568, 151.2153}, "56H"}, // Сидней
+		{Point{60.3913, 5.3221}, "32V"},    // Берген: исключение для Норвегии
+		{Point{78.2232, 15.6267}, "33X"},   // Лонгйир: исключение для Шпицбергена
+		{Point{83.9, 10}, "33X"},           // полоса X расширена до 84°
+		{Point{-80, 0}, "31C"},
+		{Point{10, 180}, "60P"},
+		{Point{84, 0}, "Z"},
+		{Point{-80.1, -10}, "A"},
+	} {
+		u, err := test.point.UTM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := u.String(); s[:len(test.zone)] != test.zone || s[len(test.zone)] != ' ' {
+			t.Errorf("%v: %s, want zone %s", test.point, s, test.zone)
+		}
+	}
+}
+
+func TestUTMRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		u, err := p.UTM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := u.Point()
+		if err != nil {
+			t.Fatalf("%v: %v", u, err)
+		}
+		if d := p.Distance(p2); d > 1e-6 {
+			t.Errorf("%v -> %v -> %v (%f km)", p, u, p2, d)
+		}
+		u2, err := ParseUTM(u.String())
+		if err != nil || u2.Zone != u.Zone || u2.Band != u.Band || math.Abs(u2.Easting-u.Easting) > 0.5 {
+			t.Errorf("bad parse of %q: %v %v", u.String(), u2, err)
+		}
+	}
+	for _, s := range []string{"", "37U 413400", "61U 413400 6179765", "37I 413400 6179765", "Z 2000000"} {
+		if _, err := ParseUTM(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestMGRS(t *testing.T) {
+	for _, test := range []struct {
+		point Point
+		mgrs  string
+	}{
+		{Point{48.8582, 2.2945}, "31UDQ4825111932"},
+		{Point{90, 0}, "ZAH0000000000"},
+		{Point{-90, 0}, "BAN0000000000"},
+	} {
+		s, err := test.point.MGRS(5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.mgrs {
+			t.Errorf("%v: %s, want %s", test.point, s, test.mgrs)
+		}
+		p, err := ParseMGRSPoint(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := p.Distance(test.point); d > 0.001 {
+			t.Errorf("%s: %v (%f km)", s, p, d)
+		}
+	}
+	// все уровни точности
+	moscow := Point{55.7539, 37.6208}
+	for digits := 0; digits <= 5; digits++ {
+		s, err := moscow.MGRS(digits)
+		if err != nil || len(s) != 5+2*digits {
+			t.Fatalf("%d: %q %v", digits, s, err)
+		}
+		u, size, err := ParseMGRS(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != math.Pow(10, float64(5-digits)) {
+			t.Errorf("%s: bad size %f", s, size)
+		}
+		mu, _ := moscow.UTM()
+		if mu.Easting < u.Easting || mu.Easting >= u.Easting+size ||
+			mu.Northing < u.Northing || mu.Northing >= u.Northing+size {
+			t.Errorf("%s: square %v does not contain point", s, u)
+		}
+	}
+	if _, err := ParseMGRSPoint("37U DB 13400 79765"); err != nil {
+		t.Error(err)
+	}
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 2000; i++ {
+		p := Point{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		s, err := p.MGRS(5)
+		if err != nil {
+			t.Fatalf("%v: %v", p, err)
+		}
+		p2, err := ParseMGRSPoint(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if d := p.Distance(p2); d > 0.0015 {
+			t.Errorf("%v -> %s -> %v (%f km)", p, s, p2, d)
+		}
+	}
+	for _, s := range []string{"", "37U", "37UDB123", "37UIB1234", "61UDB1234", "ZIH00", "37UDBabcd"} {
+		if _, _, err := ParseMGRS(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package geo
+
+import "math"
+
+// vector описывает точку на единичной сфере в декартовых координатах. Используется для
+// вычислений на сфере, где формулы в широте и долготе становятся неустойчивыми.
+type vector [3]float64
+
+// toVector возвращает единичный вектор, соответствующий точке.
+func toVector(p Point) vector {
+	lat := p[0] * (math.Pi / 180.0)
+	lon := p[1] * (math.Pi / 180.0)
+	return vector{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+// point возвращает точку, соответствующую вектору. Вектор не обязательно должен быть единичным.
+func (v vector) point() Point {
+	lat := math.Atan2(v[2], math.Hypot(v[0], v[1]))
+	lon := math.Atan2(v[1], v[0])
+	return Point{lat * (180.0 / math.Pi), lon * (180.0 / math.Pi)}
+}
+
+func (v vector) dot(v2 vector) float64 {
+	return v[0]*v2[0] + v[1]*v2[1] + v[2]*v2[2]
+}
+
+func (v vector) cross(v2 vector) vector {
+	return vector{
+		v[1]*v2[2] - v[2]*v2[1],
+		v[2]*v2[0] - v[0]*v2[2],
+		v[0]*v2[1] - v[1]*v2[0],
+	}
+}
+
+func (v vector) add(v2 vector) vector {
+	return vector{v[0] + v2[0], v[1] + v2[1], v[2] + v2[2]}
+}
+
+func (v vector) scale(k float64) vector {
+	return vector{v[0] * k, v[1] * k, v[2] * k}
+}
+
+func (v vector) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// normalize возвращает единичный вектор того же направления.
+func (v vector) normalize() vector {
+	n := v.norm()
+	if n == 0 {
+		return v
+	}
+	return v.scale(1 / n)
+}
+
+// angle возвращает угол между векторами в радианах.
+func (v vector) angle(v2 vector) float64 {
+	return math.Atan2(v.cross(v2).norm(), v.dot(v2))
+}
+
+// arcDistance возвращает расстояние в километрах от точки p до дуги большого круга между
+// точками a и b.
+func arcDistance(p, a, b vector) float64 {
+	n := a.cross(b)
+	if n.norm() == 0 { // дуга вырождена в точку
+		return p.angle(a) * erath_radius
+	}
+	n = n.normalize()
+	// проекция точки на большой круг попадает внутрь дуги
+	proj := p.add(n.scale(-p.dot(n)))
+	if a.cross(proj).dot(n) >= 0 && proj.cross(b).dot(n) >= 0 {
+		return math.Abs(math.Asin(math.Max(-1, math.Min(1, p.dot(n))))) * erath_radius
+	}
+	return math.Min(p.angle(a), p.angle(b)) * erath_radius
+}
@@ -0,0 +1,329 @@
+// Package wkb реализует чтение и запись геометрий пакета geo в формате Well-Known Binary (WKB),
+// а так же в расширенном формате PostGIS EWKB с идентификатором системы координат (SRID).
+//
+// Координаты записываются в порядке X, Y, то есть долгота, широта. При чтении поддерживаются
+// оба порядка байт, геометрии с координатами Z и M (в вариантах ISO и EWKB), но дополнительные
+// координаты отбрасываются. Пустая точка (POINT EMPTY) соответствует geo.NaNPoint.
+package wkb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/mdigger/geo"
+)
+
+// SRIDWGS84 описывает идентификатор системы координат WGS84 (EPSG:4326).
+const SRIDWGS84 = 4326
+
+// Типы геометрий WKB.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// Флаги расширенного формата EWKB.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// Marshal возвращает представление геометрии в формате WKB с порядком байт little-endian.
+func Marshal(g geo.Geometry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := write(&buf, g, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalEWKB возвращает представление геометрии в формате EWKB с указанным SRID и порядком
+// байт little-endian.
+func MarshalEWKB(g geo.Geometry, srid uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := write(&buf, g, srid); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write записывает геометрию. Если srid не равен 0, то он добавляется к заголовку.
+func write(buf *bytes.Buffer, g geo.Geometry, srid uint32) error {
+	header := func(typ uint32) {
+		buf.WriteByte(1) // little-endian
+		if srid != 0 {
+			binary.Write(buf, binary.LittleEndian, typ|ewkbSRID)
+			binary.Write(buf, binary.LittleEndian, srid)
+		} else {
+			binary.Write(buf, binary.LittleEndian, typ)
+		}
+	}
+	switch g := g.(type) {
+	case geo.Point:
+		header(wkbPoint)
+		writePoint(buf, g)
+	case geo.LineString:
+		header(wkbLineString)
+		writePoints(buf, g)
+	case geo.Polygon:
+		header(wkbPolygon)
+		writePolygon(buf, g)
+	case geo.MultiPoint:
+		header(wkbMultiPoint)
+		binary.Write(buf, binary.LittleEndian, uint32(len(g)))
+		for _, p := range g {
+			write(buf, p, 0)
+		}
+	case geo.MultiLineString:
+		header(wkbMultiLineString)
+		binary.Write(buf, binary.LittleEndian, uint32(len(g)))
+		for _, ls := range g {
+			write(buf, ls, 0)
+		}
+	case geo.MultiPolygon:
+		header(wkbMultiPolygon)
+		binary.Write(buf, binary.LittleEndian, uint32(len(g)))
+		for _, pg := range g {
+			write(buf, pg, 0)
+		}
+	case geo.Collection:
+		header(wkbGeometryCollection)
+		binary.Write(buf, binary.LittleEndian, uint32(len(g)))
+		for _, g := range g {
+			if err := write(buf, g, 0); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("wkb: unsupported geometry type %T", g)
+	}
+	return nil
+}
+
+func writePoint(buf *bytes.Buffer, p geo.Point) {
+	binary.Write(buf, binary.LittleEndian, [2]float64{p.Lon(), p.Lat()})
+}
+
+func writePoints(buf *bytes.Buffer, points []geo.Point) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(points)))
+	for _, p := range points {
+		writePoint(buf, p)
+	}
+}
+
+func writePolygon(buf *bytes.Buffer, pg geo.Polygon) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(pg)))
+	for _, r := range pg {
+		if len(r) > 0 && r[0] != r[len(r)-1] {
+			r = append(r[:len(r):len(r)], r[0]) // замыкаем контур
+		}
+		writePoints(buf, r)
+	}
+}
+
+// Unmarshal разбирает геометрию в формате WKB или EWKB и возвращает ее вместе с SRID.
+// Если SRID не указан, то возвращается 0.
+func Unmarshal(data []byte) (geo.Geometry, uint32, error) {
+	r := &reader{data: data}
+	g, srid, err := r.geometry()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(r.data) != 0 {
+		return nil, 0, errors.New("wkb: trailing data")
+	}
+	return g, srid, nil
+}
+
+// reader описывает разбор данных в формате WKB.
+type reader struct {
+	data  []byte
+	order binary.ByteOrder
+	dims  int // количество координат в точке
+}
+
+var errShort = fmt.Errorf("wkb: %v", io.ErrUnexpectedEOF)
+
+func (r *reader) uint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errShort
+	}
+	v := r.order.Uint32(r.data)
+	r.data = r.data[4:]
+	return v, nil
+}
+
+// count возвращает количество элементов, проверяя, что для них достаточно данных.
+func (r *reader) count(minSize int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(r.data)) {
+		return 0, errShort
+	}
+	return int(n), nil
+}
+
+func (r *reader) point() (geo.Point, error) {
+	if len(r.data) < 8*r.dims {
+		return geo.NaNPoint, errShort
+	}
+	lon := math.Float64frombits(r.order.Uint64(r.data))
+	lat := math.Float64frombits(r.order.Uint64(r.data[8:]))
+	r.data = r.data[8*r.dims:]
+	if math.IsNaN(lon) && math.IsNaN(lat) {
+		return geo.NaNPoint, nil // POINT EMPTY
+	}
+	p, err := geo.MakePoint(lat, lon)
+	if err != nil {
+		return p, fmt.Errorf("wkb: %v", err)
+	}
+	return p, nil
+}
+
+func (r *reader) points() ([]geo.Point, error) {
+	n, err := r.count(8 * r.dims)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]geo.Point, n)
+	for i := range points {
+		if points[i], err = r.point(); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (r *reader) polygon() (geo.Polygon, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return nil, err
+	}
+	pg := make(geo.Polygon, n)
+	for i := range pg {
+		if pg[i], err = r.points(); err != nil {
+			return nil, err
+		}
+	}
+	return pg, nil
+}
+
+// geometry разбирает геометрию вместе с заголовком.
+func (r *reader) geometry() (geo.Geometry, uint32, error) {
+	if len(r.data) < 5 {
+		return nil, 0, errShort
+	}
+	switch r.data[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, 0, fmt.Errorf("wkb: bad byte order %d", r.data[0])
+	}
+	r.data = r.data[1:]
+	typ, _ := r.uint32()
+	var srid uint32
+	if typ&ewkbSRID != 0 {
+		var err error
+		if srid, err = r.uint32(); err != nil {
+			return nil, 0, err
+		}
+	}
+	r.dims = 2
+	if typ&ewkbZ != 0 {
+		r.dims++
+	}
+	if typ&ewkbM != 0 {
+		r.dims++
+	}
+	typ &^= ewkbZ | ewkbM | ewkbSRID
+	// ISO WKB: 1000 - Z, 2000 - M, 3000 - ZM
+	if iso := typ / 1000; iso > 0 && iso <= 3 {
+		r.dims += int(iso+1) / 2
+		typ %= 1000
+	}
+	var (
+		g   geo.Geometry
+		err error
+	)
+	switch typ {
+	case wkbPoint:
+		g, err = r.point()
+	case wkbLineString:
+		var points []geo.Point
+		points, err = r.points()
+		g = geo.LineString(points)
+	case wkbPolygon:
+		g, err = r.polygon()
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		g, err = r.collection(typ)
+	default:
+		return nil, 0, fmt.Errorf("wkb: unsupported geometry type %d", typ)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return g, srid, nil
+}
+
+// collection разбирает набор геометрий указанного типа.
+func (r *reader) collection(typ uint32) (geo.Geometry, error) {
+	n, err := r.count(5)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		mp = make(geo.MultiPoint, 0, n)
+		ml = make(geo.MultiLineString, 0, n)
+		mg = make(geo.MultiPolygon, 0, n)
+		c  = make(geo.Collection, 0, n)
+	)
+	for i := 0; i < n; i++ {
+		g, _, err := r.geometry()
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		switch typ {
+		case wkbMultiPoint:
+			var p geo.Point
+			p, ok = g.(geo.Point)
+			mp = append(mp, p)
+		case wkbMultiLineString:
+			var ls geo.LineString
+			ls, ok = g.(geo.LineString)
+			ml = append(ml, ls)
+		case wkbMultiPolygon:
+			var pg geo.Polygon
+			pg, ok = g.(geo.Polygon)
+			mg = append(mg, pg)
+		default:
+			c, ok = append(c, g), true
+		}
+		if !ok {
+			return nil, fmt.Errorf("wkb: unexpected %T in collection type %d", g, typ)
+		}
+	}
+	switch typ {
+	case wkbMultiPoint:
+		return mp, nil
+	case wkbMultiLineString:
+		return ml, nil
+	case wkbMultiPolygon:
+		return mg, nil
+	}
+	return c, nil
+}
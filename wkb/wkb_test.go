@@ -0,0 +1,107 @@
+package wkb
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdigger/geo"
+)
+
+func TestKnownVectors(t *testing.T) {
+	for _, test := range []struct {
+		hex  string
+		g    geo.Geometry
+		srid uint32
+	}{
+		// POINT(1 2), little-endian
+		{"0101000000000000000000F03F0000000000000040", geo.NewPoint(2, 1), 0},
+		// POINT(2 4), big-endian
+		{"000000000140000000000000004010000000000000", geo.NewPoint(4, 2), 0},
+		// SRID=4326;POINT(1 2), EWKB
+		{"0101000020E6100000000000000000F03F0000000000000040", geo.NewPoint(2, 1), SRIDWGS84},
+		// POINT Z (1 2 3), ISO
+		{"01E9030000000000000000F03F00000000000000400000000000000840", geo.NewPoint(2, 1), 0},
+		// POINT Z (1 2 3), EWKB
+		{"0101000080000000000000F03F00000000000000400000000000000840", geo.NewPoint(2, 1), 0},
+		// LINESTRING(30 10, 10 30)
+		{"010200000002000000" + "0000000000003E40" + "0000000000002440" +
+			"0000000000002440" + "0000000000003E40", geo.LineString{{10, 30}, {30, 10}}, 0},
+	} {
+		data, err := hex.DecodeString(test.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, srid, err := Unmarshal(data)
+		if err != nil {
+			t.Errorf("%s: %v", test.hex, err)
+			continue
+		}
+		if !reflect.DeepEqual(g, test.g) || srid != test.srid {
+			t.Errorf("%s: %v (SRID %d)", test.hex, g, srid)
+		}
+	}
+	data, err := MarshalEWKB(geo.NewPoint(2, 1), SRIDWGS84)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := strings.ToUpper(hex.EncodeToString(data)); h != "0101000020E6100000000000000000F03F0000000000000040" {
+		t.Errorf("bad EWKB: %s", h)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	square := geo.Ring{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}
+	for _, g := range []geo.Geometry{
+		geo.NewPoint(55.7558, 37.6173),
+		geo.LineString{{55, 37}, {56, 38}, {57, 39}},
+		geo.Polygon{square, {{0.2, 0.2}, {0.8, 0.2}, {0.8, 0.8}, {0.2, 0.2}}},
+		geo.MultiPoint{{55, 37}, {56, 38}},
+		geo.MultiLineString{{{55, 37}, {56, 38}}, {{10, 20}, {11, 21}}},
+		geo.MultiPolygon{{square}, {square}},
+		geo.Collection{geo.NewPoint(1, 2), geo.LineString{{3, 4}, {5, 6}}, geo.MultiPoint{}},
+	} {
+		for _, srid := range []uint32{0, SRIDWGS84} {
+			data, err := MarshalEWKB(g, srid)
+			if err != nil {
+				t.Fatalf("%T: %v", g, err)
+			}
+			g2, srid2, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("%T: %v", g, err)
+			}
+			if !reflect.DeepEqual(g, g2) || srid != srid2 {
+				t.Errorf("bad round-trip: %v != %v (SRID %d)", g, g2, srid2)
+			}
+		}
+	}
+	// пустая точка
+	data, _ := Marshal(geo.NaNPoint)
+	if g, _, err := Unmarshal(data); err != nil || !math.IsNaN(g.(geo.Point).Lat()) {
+		t.Error("bad empty point:", g, err)
+	}
+	// незамкнутый контур замыкается при записи
+	data, _ = Marshal(geo.Polygon{square[:4]})
+	if g, _, _ := Unmarshal(data); !reflect.DeepEqual(g, geo.Polygon{square}) {
+		t.Errorf("ring is not closed: %v", g)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, h := range []string{
+		"",
+		"02",
+		"0101000000000000000000F03F",
+		"0109000000",
+		"0101000000000000000000F03F000000000000004000", // лишние данные
+		"0101000000000000000000F03F0000000000005940",   // широта 100
+		"0104000000FFFFFFFF",
+	} {
+		data, _ := hex.DecodeString(h)
+		if _, _, err := Unmarshal(data); err == nil {
+			t.Errorf("%s: expected error", h)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+// Package wkt реализует чтение и запись геометрий пакета geo в формате Well-Known Text (WKT).
+//
+// Координаты записываются в порядке X Y, то есть долгота, широта. При чтении допускается
+// префикс EWKT "SRID=4326;", а так же геометрии с координатами Z и M, но дополнительные
+// координаты отбрасываются. Пустая точка (POINT EMPTY) соответствует geo.NaNPoint.
+package wkt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mdigger/geo"
+)
+
+// Marshal возвращает представление геометрии в формате WKT. Пустая точка (geo.NaNPoint)
+// записывается как POINT EMPTY, а для остальных геометрий с пустыми точками или бесконечными
+// координатами возвращается ошибка.
+func Marshal(g geo.Geometry) (string, error) {
+	var b strings.Builder
+	if err := write(&b, g); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// write записывает геометрию вместе с названием типа.
+func write(b *strings.Builder, g geo.Geometry) error {
+	if err := check(g); err != nil {
+		return err
+	}
+	switch g := g.(type) {
+	case geo.Point:
+		b.WriteString("POINT")
+		if math.IsNaN(g.Lat()) || math.IsNaN(g.Lon()) {
+			b.WriteString(" EMPTY")
+			return nil
+		}
+		b.WriteByte('(')
+		writePoint(b, g)
+		b.WriteByte(')')
+	case geo.LineString:
+		b.WriteString("LINESTRING")
+		writePoints(b, g)
+	case geo.Polygon:
+		b.WriteString("POLYGON")
+		writePolygon(b, g)
+	case geo.MultiPoint:
+		b.WriteString("MULTIPOINT")
+		writePoints(b, g)
+	case geo.MultiLineString:
+		b.WriteString("MULTILINESTRING")
+		writeList(b, len(g), func(i int) { writePoints(b, g[i]) })
+	case geo.MultiPolygon:
+		b.WriteString("MULTIPOLYGON")
+		writeList(b, len(g), func(i int) { writePolygon(b, g[i]) })
+	case geo.Collection:
+		b.WriteString("GEOMETRYCOLLECTION")
+		var err error
+		writeList(b, len(g), func(i int) {
+			if e := write(b, g[i]); e != nil && err == nil {
+				err = e
+			}
+		})
+		return err
+	default:
+		return fmt.Errorf("wkt: unsupported geometry type %T", g)
+	}
+	return nil
+}
+
+// check возвращает ошибку, если координаты геометрии нельзя записать в WKT: бесконечные
+// координаты и пустые точки внутри линий, многоугольников и наборов точек. Пустой может быть
+// только отдельная точка (POINT EMPTY).
+func check(g geo.Geometry) error {
+	var points [][]geo.Point
+	switch g := g.(type) {
+	case geo.Point:
+		if math.IsNaN(g.Lat()) || math.IsNaN(g.Lon()) {
+			return nil
+		}
+		points = [][]geo.Point{{g}}
+	case geo.LineString:
+		points = [][]geo.Point{g}
+	case geo.MultiPoint:
+		points = [][]geo.Point{g}
+	case geo.MultiLineString:
+		for _, ls := range g {
+			points = append(points, ls)
+		}
+	case geo.Polygon:
+		for _, r := range g {
+			points = append(points, r)
+		}
+	case geo.MultiPolygon:
+		for _, pg := range g {
+			for _, r := range pg {
+				points = append(points, r)
+			}
+		}
+	}
+	for _, list := range points {
+		for _, p := range list {
+			if !finite(p.Lat()) || !finite(p.Lon()) {
+				return fmt.Errorf("wkt: %T contains empty point or infinite coordinates", g)
+			}
+		}
+	}
+	return nil
+}
+
+// finite возвращает true, если значение не NaN и не бесконечность.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// writeList записывает список из n элементов в скобках или EMPTY, если список пустой.
+func writeList(b *strings.Builder, n int, item func(i int)) {
+	if n == 0 {
+		b.WriteString(" EMPTY")
+		return
+	}
+	b.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		item(i)
+	}
+	b.WriteByte(')')
+}
+
+func writePoint(b *strings.Builder, p geo.Point) {
+	b.WriteString(strconv.FormatFloat(p.Lon(), 'f', -1, 64))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(p.Lat(), 'f', -1, 64))
+}
+
+func writePoints(b *strings.Builder, points []geo.Point) {
+	writeList(b, len(points), func(i int) { writePoint(b, points[i]) })
+}
+
+func writePolygon(b *strings.Builder, pg geo.Polygon) {
+	writeList(b, len(pg), func(i int) {
+		r := pg[i]
+		if len(r) > 0 && r[0] != r[len(r)-1] {
+			r = append(r[:len(r):len(r)], r[0]) // замыкаем контур
+		}
+		writePoints(b, r)
+	})
+}
+
+// Unmarshal разбирает геометрию в формате WKT или EWKT и возвращает ее вместе с SRID.
+// Если SRID не указан, то возвращается 0.
+func Unmarshal(s string) (geo.Geometry, uint32, error) {
+	var srid uint32
+	s = strings.TrimSpace(s)
+	if len(s) > 5 && strings.EqualFold(s[:5], "SRID=") {
+		i := strings.IndexByte(s, ';')
+		if i < 0 {
+			return nil, 0, errors.New("wkt: bad SRID")
+		}
+		v, err := strconv.ParseUint(s[5:i], 10, 32)
+		if err != nil {
+			return nil, 0, fmt.Errorf("wkt: bad SRID: %q", s[5:i])
+		}
+		srid, s = uint32(v), s[i+1:]
+	}
+	p := &parser{s: s}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, 0, err
+	}
+	if p.skipSpaces(); p.pos < len(p.s) {
+		return nil, 0, p.errorf("unexpected data")
+	}
+	return g, srid, nil
+}
+
+// parser описывает разбор текста в формате WKT.
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("wkt: %s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// word возвращает следующее слово в верхнем регистре.
+func (p *parser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos] | 0x20 // нижний регистр для букв
+		if c < 'a' || c > 'z' {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+// peek возвращает следующий значащий символ, не сдвигая позицию разбора.
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// empty проверяет наличие ключевого слова EMPTY или открывающей скобки.
+func (p *parser) empty() (bool, error) {
+	if p.peek() == '(' {
+		p.pos++
+		return false, nil
+	}
+	if p.word() == "EMPTY" {
+		return true, nil
+	}
+	return false, p.errorf("expected '(' or EMPTY")
+}
+
+// list разбирает список элементов в скобках, разделенных запятыми.
+func (p *parser) list(item func() error) error {
+	empty, err := p.empty()
+	if err != nil || empty {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return nil
+		default:
+			return p.errorf("expected ',' or ')'")
+		}
+	}
+}
+
+// point разбирает координаты точки: X Y с возможными Z и M.
+func (p *parser) point() (geo.Point, error) {
+	var coords []float64
+	for {
+		p.skipSpaces()
+		start := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		if start == p.pos {
+			break
+		}
+		v, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return geo.NaNPoint, p.errorf("bad number %q", p.s[start:p.pos])
+		}
+		coords = append(coords, v)
+	}
+	if len(coords) < 2 || len(coords) > 4 {
+		return geo.NaNPoint, p.errorf("bad point")
+	}
+	point, err := geo.MakePoint(coords[1], coords[0])
+	if err != nil {
+		return point, p.errorf("%v", err)
+	}
+	return point, nil
+}
+
+func (p *parser) points() ([]geo.Point, error) {
+	points := []geo.Point{}
+	err := p.list(func() error {
+		// в MULTIPOINT координаты точек могут быть в скобках
+		bracket := p.peek() == '('
+		if bracket {
+			p.pos++
+		}
+		point, err := p.point()
+		if err != nil {
+			return err
+		}
+		points = append(points, point)
+		if bracket {
+			return p.expect(')')
+		}
+		return nil
+	})
+	return points, err
+}
+
+func (p *parser) polygon() (geo.Polygon, error) {
+	pg := geo.Polygon{}
+	err := p.list(func() error {
+		r, err := p.points()
+		pg = append(pg, r)
+		return err
+	})
+	return pg, err
+}
+
+// geometry разбирает геометрию вместе с названием типа.
+func (p *parser) geometry() (geo.Geometry, error) {
+	typ := p.word()
+	// модификаторы размерности: POINT Z, POINT M, POINT ZM
+	if c := p.peek() | 0x20; c == 'z' || c == 'm' {
+		if dim := p.word(); dim != "Z" && dim != "M" && dim != "ZM" {
+			return nil, p.errorf("bad dimension %q", dim)
+		}
+	}
+	switch strings.TrimRight(typ, "ZM") {
+	case "POINT":
+		empty, err := p.empty()
+		if err != nil || empty {
+			return geo.NaNPoint, err
+		}
+		point, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		return point, p.expect(')')
+	case "LINESTRING":
+		points, err := p.points()
+		return geo.LineString(points), err
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOINT":
+		points, err := p.points()
+		return geo.MultiPoint(points), err
+	case "MULTILINESTRING":
+		ml := geo.MultiLineString{}
+		err := p.list(func() error {
+			points, err := p.points()
+			ml = append(ml, points)
+			return err
+		})
+		return ml, err
+	case "MULTIPOLYGON":
+		mp := geo.MultiPolygon{}
+		err := p.list(func() error {
+			pg, err := p.polygon()
+			mp = append(mp, pg)
+			return err
+		})
+		return mp, err
+	case "GEOMETRYCOLLECTION":
+		c := geo.Collection{}
+		err := p.list(func() error {
+			g, err := p.geometry()
+			c = append(c, g)
+			return err
+		})
+		return c, err
+	default:
+		return nil, p.errorf("unsupported geometry type %q", typ)
+	}
+}
@@ -0,0 +1,106 @@
+package wkt
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mdigger/geo"
+)
+
+func TestKnownVectors(t *testing.T) {
+	for _, test := range []struct {
+		wkt  string
+		g    geo.Geometry
+		srid uint32
+	}{
+		{"POINT(30 10)", geo.NewPoint(10, 30), 0},
+		{"point z (30 10 5)", geo.NewPoint(10, 30), 0},
+		{"SRID=4326;POINT(37.6173 55.7558)", geo.NewPoint(55.7558, 37.6173), 4326},
+		{"POINT EMPTY", geo.NaNPoint, 0},
+		{"LINESTRING (30 10, 10 30, 40 40)", geo.LineString{{10, 30}, {30, 10}, {40, 40}}, 0},
+		{"POLYGON ((30 10, 40 40, 20 40, 10 20, 30 10))",
+			geo.Polygon{{{10, 30}, {40, 40}, {40, 20}, {20, 10}, {10, 30}}}, 0},
+		{"MULTIPOINT ((10 40), (40 30))", geo.MultiPoint{{40, 10}, {30, 40}}, 0},
+		{"MULTIPOINT (10 40, 40 30)", geo.MultiPoint{{40, 10}, {30, 40}}, 0},
+		{"MULTILINESTRING ((10 10, 20 20), (40 40, 30 30))",
+			geo.MultiLineString{{{10, 10}, {20, 20}}, {{40, 40}, {30, 30}}}, 0},
+		{"MULTIPOLYGON (((30 20, 45 40, 10 40, 30 20)), ((15 5, 40 10, 10 20, 5 10, 15 5)))",
+			geo.MultiPolygon{
+				{{{20, 30}, {40, 45}, {40, 10}, {20, 30}}},
+				{{{5, 15}, {10, 40}, {20, 10}, {10, 5}, {5, 15}}},
+			}, 0},
+		{"GEOMETRYCOLLECTION (POINT (40 10), LINESTRING (10 10, 20 20))",
+			geo.Collection{geo.NewPoint(10, 40), geo.LineString{{10, 10}, {20, 20}}}, 0},
+		{"GEOMETRYCOLLECTION EMPTY", geo.Collection{}, 0},
+	} {
+		g, srid, err := Unmarshal(test.wkt)
+		if err != nil {
+			t.Errorf("%s: %v", test.wkt, err)
+			continue
+		}
+		if p, ok := g.(geo.Point); ok && math.IsNaN(p.Lat()) {
+			if !math.IsNaN(test.g.(geo.Point).Lat()) {
+				t.Errorf("%s: %v", test.wkt, g)
+			}
+		} else if !reflect.DeepEqual(g, test.g) || srid != test.srid {
+			t.Errorf("%s: %v (SRID %d)", test.wkt, g, srid)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	for _, test := range []struct {
+		g   geo.Geometry
+		wkt string
+	}{
+		{geo.NewPoint(55.7558, 37.6173), "POINT(37.6173 55.7558)"},
+		{geo.NaNPoint, "POINT EMPTY"},
+		{geo.LineString{{10, 30}, {30, 10}}, "LINESTRING(30 10,10 30)"},
+		{geo.Polygon{{{0, 0}, {0, 1}, {1, 1}}}, "POLYGON((0 0,1 0,1 1,0 0))"},
+		{geo.MultiPoint{}, "MULTIPOINT EMPTY"},
+		{geo.Collection{geo.NewPoint(1, 2), geo.MultiLineString{{{3, 4}, {5, 6}}}},
+			"GEOMETRYCOLLECTION(POINT(2 1),MULTILINESTRING((4 3,6 5)))"},
+	} {
+		s, err := Marshal(test.g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.wkt {
+			t.Errorf("bad WKT: %s, want %s", s, test.wkt)
+		}
+		if _, _, err := Unmarshal(s); err != nil {
+			t.Errorf("%s: %v", s, err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"POINT",
+		"POINT(1)",
+		"POINT(1 2",
+		"POINT(1 95)",
+		"CIRCLE(1 2)",
+		"LINESTRING(1 2, 3 4) foo",
+		"SRID=abc;POINT(1 2)",
+		"POINT Q (1 2)",
+	} {
+		if g, _, err := Unmarshal(s); err == nil {
+			t.Errorf("%q: expected error, got %v", s, g)
+		}
+	}
+	inf := geo.Point{math.Inf(1), 0}
+	for _, g := range []geo.Geometry{
+		geo.LineString{geo.NewPoint(1, 2), geo.NaNPoint},
+		geo.Polygon{{geo.NewPoint(0, 0), geo.NewPoint(0, 1), geo.NaNPoint}},
+		geo.MultiPoint{geo.NaNPoint},
+		geo.Collection{geo.NaNPoint, geo.MultiLineString{{inf}}},
+		inf,
+	} {
+		if s, err := Marshal(g); err == nil {
+			t.Errorf("%v: expected error, got %q", g, s)
+		}
+	}
+}